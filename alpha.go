@@ -4,8 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"log"
 	"sort"
+	"strings"
+	"time"
 )
 
 // offsetOfMismatch returns the offset of the first bytes that do not match from
@@ -31,26 +32,64 @@ func offsetOfMismatch(s1, s2 string) int {
 
 type Alpha struct {
 	prefix   string
-	value    interface{}
-	children []*Alpha // sorted here based on their prefixes
+	value    interface{} // the *alphaHeader of a root node, if any
+	children []*Alpha    // sorted here based on their prefixes
+}
+
+// alphaHeader holds the options of a Trie. Only the root node needs them, and
+// it holds no value of its own, so it keeps the header in its value field
+// rather than every node having fields for the options.
+type alphaHeader struct {
+	fold     func(string) string
+	tracer   Tracer
+	observer Observer
+}
+
+// header returns the header of the Trie, or nil when it has none.
+func (tn *Alpha) header() *alphaHeader {
+	h, _ := tn.value.(*alphaHeader)
+	return h
+}
+
+// ensureHeader returns the header of the Trie, after giving the Trie a header
+// when it has none.
+func (tn *Alpha) ensureHeader() *alphaHeader {
+	h := tn.header()
+	if h == nil {
+		h = new(alphaHeader)
+		tn.value = h
+	}
+	return h
+}
+
+// folder returns the fold function of the Trie, or nil when it does not fold
+// keys.
+func (tn *Alpha) folder() func(string) string {
+	if h := tn.header(); h != nil {
+		return h.fold
+	}
+	return nil
+}
+
+// newRoot returns a new root node with the specified children, which folds
+// keys the same way as the Trie.
+func (tn *Alpha) newRoot(children []*Alpha) *Alpha {
+	root := &Alpha{children: children}
+	if fold := tn.folder(); fold != nil {
+		root.value = &alphaHeader{fold: fold}
+	}
+	return root
 }
 
 // searchChildren returns the index of the child node that the key should be
 // found inside.
 func (tn *Alpha) searchChildren(key string) int {
-	const debug = false
-	if debug {
-		log.Printf("searching for %q at %+v", key, tn)
-	}
 	i := sort.Search(len(tn.children), func(i int) bool {
 		return tn.children[i].prefix >= key
 	})
 	// NOTE: Index could be to the left, e.g. "samuel" sorts after "sally",
 	// but ought to cause a split at "sa".
 	if j := i - 1; j >= 0 && offsetOfMismatch(key, tn.children[j].prefix) > 0 {
-		if debug {
-			log.Printf("moving to the left due to shared prefix")
-		}
 		return j
 	}
 	return i
@@ -58,181 +97,350 @@ func (tn *Alpha) searchChildren(key string) int {
 
 // Delete removes the specified key value pair from the Trie.
 func (tn *Alpha) Delete(key string) {
-	const debug = false
+	o := Observation{Op: OpDelete}
+	observer := tn.observer()
+	if observer == nil {
+		tn.delete(key, &o)
+		return
+	}
+	start := time.Now()
+	tn.delete(key, &o)
+	observe(observer, start, &o)
+}
+
+// delete removes the specified key value pair from the Trie, and records what
+// it did in o.
+func (tn *Alpha) delete(key string, o *Observation) {
+	tracer := tn.tracer()
+
+	if fold := tn.folder(); fold != nil {
+		key = fold(key)
+	}
 
 	curr := tn // start at this node
 	var previous *Alpha
 	var childIndex int
 
 	for {
-		if debug {
-			log.Printf("delete %q at %+v", key, curr)
-		}
-
+		o.Nodes++
 		i := offsetOfMismatch(key, curr.prefix)
 
 		if i < len(curr.prefix) {
-			if debug {
-				log.Printf("fewer characters shared than curr.prefix: %q %q", curr.prefix[:i], curr.prefix)
+			// fewer characters shared than curr.prefix
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Delete", Kind: TraceMiss, Key: key, Prefix: curr.prefix})
 			}
 			return
 		}
 
-		if debug {
-			log.Printf("i: %d; len(curr.children): %d", i, len(curr.children))
-		}
-
 		if i == len(key) && len(curr.children) > 0 && curr.children[0].prefix == "" {
-			if debug {
-				log.Printf("delete 0th element from children: %+v", curr.children[0])
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Delete", Kind: TraceRemove, Prefix: curr.prefix})
 			}
-			copy(curr.children[0:], curr.children[1:])
-			curr.children[len(curr.children)-1] = nil
-			curr.children = curr.children[:len(curr.children)-1]
-
-			switch len(curr.children) {
-			case 0:
-				if debug {
-					log.Printf("delete %d element from previous' children: %+v", childIndex, previous.children[childIndex])
-				}
-				copy(previous.children[childIndex:], previous.children[childIndex+1:])
-				previous.children[len(previous.children)-1] = nil
-				previous.children = previous.children[:len(previous.children)-1]
-
-				if len(previous.children) == 1 {
-					if debug {
-						log.Printf("merge previous with its only child: %+v; %+v", previous, previous.children[0])
-					}
-					previous.prefix += previous.children[0].prefix
-					previous.children[0].prefix = ""
-					previous.children[0].value = previous.children[0].children[0].value
-					previous.children[0].children = previous.children[0].children[:0]
+			o.Hit = true
+			curr.removeChildAtIndex(0)
+
+			switch {
+			case curr == tn:
+				// The root node may have any number of children.
+			case len(curr.children) == 0:
+				if tracer != nil {
+					tracer.Trace(TraceEvent{Op: "Delete", Kind: TraceRemove, Prefix: curr.prefix})
 				}
-			case 1:
-				if debug {
-					log.Printf("merge current with its only child: %+v; %+v", curr, curr.children[0])
-					for _, child := range curr.children {
-						log.Printf("    %+v", child)
-					}
+				previous.removeChildAtIndex(childIndex)
+				if previous != tn {
+					o.Merge = previous.traceMergeOnlyChild(tracer, "Delete")
 				}
-				curr.prefix += curr.children[0].prefix
-				curr.children[0].prefix = ""
-				curr.children[0].value = curr.children[0].children[0].value
-				curr.children[0].children = curr.children[0].children[:0]
+			default:
+				o.Merge = curr.traceMergeOnlyChild(tracer, "Delete")
 			}
 
 			return
 		}
 
 		suffix := key[i:]
-		if debug {
-			log.Printf("shared %d; prefix: %q; suffix: %q", i, key[:i], suffix)
-		}
-
 		childIndex = curr.searchChildren(suffix)
 		if childIndex == len(curr.children) {
 			// When insertion point is beyond right end of slice, then the
 			// key is not found.
-			if debug {
-				log.Printf("key not found: %q", key)
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Delete", Kind: TraceMiss, Key: suffix, Prefix: curr.prefix})
 			}
 			return
 		}
 		// Next visit the child most likely to have this key.
-		if debug {
-			log.Printf("going to child: %d", childIndex)
-		}
 		previous = curr
 		curr = curr.children[childIndex]
 		key = suffix
+		if tracer != nil {
+			tracer.Trace(TraceEvent{Op: "Delete", Kind: TraceDescend, Key: key, Prefix: curr.prefix})
+		}
+	}
+}
+
+// deletePrefix removes every key value pair whose key has the specified prefix
+// from the Trie, and returns the number of pairs removed.
+func (tn *Alpha) deletePrefix(prefix string) int {
+	if fold := tn.folder(); fold != nil {
+		prefix = fold(prefix)
+	}
+
+	curr := tn // start at this node
+	var previous *Alpha
+	var childIndex int
+
+	for {
+		i := offsetOfMismatch(prefix, curr.prefix)
+		if i == len(prefix) {
+			break // every key below curr has the prefix
+		}
+		if i < len(curr.prefix) {
+			return 0
+		}
+		prefix = prefix[i:]
+		childIndex = curr.searchChildren(prefix)
+		if childIndex == len(curr.children) || curr.children[childIndex].prefix == "" {
+			return 0
+		}
+		previous = curr
+		curr = curr.children[childIndex]
+	}
+
+	count := curr.keyCount()
+	if curr == tn {
+		tn.children = nil
+		return count
 	}
+	previous.removeChildAtIndex(childIndex)
+	if previous != tn {
+		previous.mergeOnlyChild()
+	}
+	return count
 }
 
 // Load returns the value associated with the specified key, along with a
 // boolean which is true when the trie has the specified key.
 func (tn *Alpha) Load(key string) (interface{}, bool) {
-	const debug = false
+	o := Observation{Op: OpLoad}
+	observer := tn.observer()
+	if observer == nil {
+		return tn.load(key, &o)
+	}
+	start := time.Now()
+	value, ok := tn.load(key, &o)
+	observe(observer, start, &o)
+	return value, ok
+}
+
+// load returns the value associated with the specified key, along with a
+// boolean which is true when the trie has the specified key, and records what
+// it did in o.
+func (tn *Alpha) load(key string, o *Observation) (interface{}, bool) {
+	tracer := tn.tracer()
+
+	fold := tn.folder()
+	if fold != nil {
+		key = fold(key)
+	}
 
 	curr := tn // start at this node
 
 	for {
-		if debug {
-			log.Printf("load %q at %+v", key, curr)
-		}
-
+		o.Nodes++
 		i := offsetOfMismatch(key, curr.prefix)
 
 		if i < len(curr.prefix) {
-			if debug {
-				log.Printf("fewer characters shared than curr.prefix: %q %q", curr.prefix[:i], curr.prefix)
+			// fewer characters shared than curr.prefix
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Load", Kind: TraceMiss, Key: key, Prefix: curr.prefix})
 			}
 			return nil, false
 		}
 
-		if debug {
-			log.Printf("i: %d; len(curr.children): %d", i, len(curr.children))
-		}
-
 		if i == len(key) && len(curr.children) > 0 && curr.children[0].prefix == "" {
-			if debug {
-				log.Printf("found value: %+v", curr.children[0])
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Load", Kind: TraceFound, Prefix: curr.prefix})
+			}
+			o.Hit = true
+			if fold != nil {
+				return curr.children[0].value.(*foldedValue).value, true
 			}
 			return curr.children[0].value, true
 		}
 
 		suffix := key[i:]
-		if debug {
-			log.Printf("shared %d; prefix: %q; suffix: %q", i, key[:i], suffix)
-		}
-
 		i = curr.searchChildren(suffix)
 		if i == len(curr.children) {
 			// When insertion point is beyond right end of slice, then the
 			// key is not found.
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Load", Kind: TraceMiss, Key: suffix, Prefix: curr.prefix})
+			}
 			return nil, false
 		}
 		// Next visit the child most likely to have this key.
-		if debug {
-			log.Printf("going to child: %d", i)
-		}
 		curr = curr.children[i]
 		key = suffix
+		if tracer != nil {
+			tracer.Trace(TraceEvent{Op: "Load", Kind: TraceDescend, Key: key, Prefix: curr.prefix})
+		}
+	}
+}
+
+// LongestPrefix returns the longest key stored in the Trie that is a prefix of
+// the specified key, along with its value and a boolean which is true when such
+// a key was found. For a folding Trie, the key is returned as it was spelled
+// when it was stored.
+func (tn *Alpha) LongestPrefix(key string) (string, interface{}, bool) {
+	folded := key
+	fold := tn.folder()
+	if fold != nil {
+		folded = fold(key)
+	}
+	length, node := tn.longestPrefix(folded, nil)
+	if node == nil {
+		return "", nil, false
+	}
+	if fold != nil {
+		fv := node.value.(*foldedValue)
+		return fv.key, fv.value, true
+	}
+	return key[:length], node.value, true
+}
+
+// longestPrefix returns the length of the longest key stored in the Trie that is
+// a prefix of the specified key, along with the node that holds its value, or
+// nil when no such key is stored. When accept is not nil, only those prefixes
+// whose lengths it accepts are considered.
+func (tn *Alpha) longestPrefix(key string, accept func(length int) bool) (int, *Alpha) {
+	var match *Alpha
+	var length, consumed int
+
+	curr := tn // start at this node
+
+	for {
+		i := offsetOfMismatch(key, curr.prefix)
+		if i < len(curr.prefix) {
+			return length, match
+		}
+		consumed += i
+		key = key[i:]
+
+		if len(curr.children) > 0 && curr.children[0].prefix == "" && (accept == nil || accept(consumed)) {
+			match, length = curr.children[0], consumed
+		}
+		if key == "" {
+			return length, match
+		}
+
+		i = curr.searchChildren(key)
+		if i == len(curr.children) {
+			return length, match
+		}
+		curr = curr.children[i]
+	}
+}
+
+// prefixes invokes fn with the length and value of each key stored in the Trie
+// that is a prefix of the specified key, from the shortest to the longest,
+// until fn returns false.
+func (tn *Alpha) prefixes(key string, fn func(length int, value interface{}) bool) {
+	var consumed int
+
+	curr := tn // start at this node
+
+	for {
+		i := offsetOfMismatch(key, curr.prefix)
+		if i < len(curr.prefix) {
+			return
+		}
+		consumed += i
+		key = key[i:]
+
+		if len(curr.children) > 0 && curr.children[0].prefix == "" && !fn(consumed, curr.children[0].value) {
+			return
+		}
+		if key == "" {
+			return
+		}
+
+		i = curr.searchChildren(key)
+		if i == len(curr.children) {
+			return
+		}
+		curr = curr.children[i]
 	}
 }
 
 // Store stores the specified key and value in the Trie.
 func (tn *Alpha) Store(key string, value interface{}) {
-	const debug = false
+	tn.store(key, value, false)
+}
+
+// store stores the specified key and value in the Trie. When borrowed is true,
+// key shares its storage with a byte slice owned by the client, and only copies
+// of it may be retained by the Trie.
+func (tn *Alpha) store(key string, value interface{}, borrowed bool) {
+	o := Observation{Op: OpStore}
+	observer := tn.observer()
+	if observer == nil {
+		tn.storeObserved(key, value, borrowed, &o)
+		return
+	}
+	start := time.Now()
+	tn.storeObserved(key, value, borrowed, &o)
+	observe(observer, start, &o)
+}
+
+// storeObserved stores the specified key and value in the Trie, like store, and
+// records what it did in o.
+func (tn *Alpha) storeObserved(key string, value interface{}, borrowed bool, o *Observation) {
+	tracer := tn.tracer()
+
+	if fold := tn.folder(); fold != nil {
+		original := key
+		if borrowed {
+			original = cloneString(key)
+		}
+		value = &foldedValue{key: original, value: value}
+		key = fold(key)
+	}
 
 	curr := tn // start at this node
 	var previous *Alpha
 	var childIndex int
 
 	for {
-		if debug {
-			log.Printf("store %q at %+v; previous: %+v", key, curr, previous)
-		}
-
+		o.Nodes++
 		i := offsetOfMismatch(key, curr.prefix)
-		if debug {
-			log.Printf("shared: %d; %q", i, key[:i])
-		}
 
 		if i < len(curr.prefix) {
 			// "sally" --> "sam", shared will be "sa"
-			shared := key[:i]
-			if debug {
-				log.Printf("need to split: %+v; shared: %q", curr, shared)
+			shared := curr.prefix[:i]
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Store", Kind: TraceSplit, Key: key, Prefix: shared})
 			}
+			o.Split = true
 			newParent := &Alpha{
 				prefix:   shared,
 				children: make([]*Alpha, 2),
 			}
+			curr.prefix = curr.prefix[i:]
+			if i == len(key) {
+				// "sam" --> "samuel", value stored directly below shared
+				newParent.children[0] = &Alpha{value: value}
+				newParent.children[1] = curr
+				previous.children[childIndex] = newParent
+				return
+			}
+			suffix := key[i:]
+			if borrowed {
+				suffix = cloneString(suffix)
+			}
 			newNode := &Alpha{
-				prefix:   key[i:],
+				prefix:   suffix,
 				children: []*Alpha{&Alpha{value: value}},
 			}
-			curr.prefix = curr.prefix[i:]
 			if newNode.prefix < curr.prefix {
 				newParent.children[0] = newNode
 				newParent.children[1] = curr
@@ -241,28 +449,22 @@ func (tn *Alpha) Store(key string, value interface{}) {
 				newParent.children[1] = newNode
 			}
 			// re-wire parent to newNode
-			if debug {
-				log.Printf("childIndex: %d", childIndex)
-				log.Printf("previous:\n%s", string(previous.Bytes()))
-				log.Printf("newParent:\n%s", string(newParent.Bytes()))
-			}
 			previous.children[childIndex] = newParent
 			return
 		}
 
 		if i == len(key) {
-			if debug {
-				log.Printf("entire key here")
-			}
+			// entire key here
 			if len(curr.children) > 0 && curr.children[0].prefix == "" {
-				if debug {
-					log.Printf("updating value at first child")
+				if tracer != nil {
+					tracer.Trace(TraceEvent{Op: "Store", Kind: TraceUpdate, Prefix: curr.prefix})
 				}
+				o.Hit = true
 				curr.children[0].value = value
 				return
 			}
-			if debug {
-				log.Printf("inserting new child to store value")
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Store", Kind: TraceInsert, Prefix: ""})
 			}
 			curr.insertChildAtIndex(0, &Alpha{value: value})
 			return
@@ -272,12 +474,12 @@ func (tn *Alpha) Store(key string, value interface{}) {
 		childIndex = curr.searchChildren(suffix)
 
 		// does new key share any characters with the specified child?
-		if debug {
-			log.Printf("childIndex: %d", childIndex)
-		}
 		if childIndex == len(curr.children) || offsetOfMismatch(suffix, curr.children[childIndex].prefix) == 0 {
-			if debug {
-				log.Printf("inserting new child to store value")
+			if borrowed {
+				suffix = cloneString(suffix)
+			}
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Store", Kind: TraceInsert, Prefix: suffix})
 			}
 			curr.insertChildAtIndex(childIndex, &Alpha{
 				prefix:   suffix,
@@ -287,12 +489,12 @@ func (tn *Alpha) Store(key string, value interface{}) {
 		}
 
 		// go visit the child
-		if debug {
-			log.Printf("going to child: %d", childIndex)
-		}
 		previous = curr
 		curr = curr.children[childIndex]
 		key = suffix
+		if tracer != nil {
+			tracer.Trace(TraceEvent{Op: "Store", Kind: TraceDescend, Key: key, Prefix: curr.prefix})
+		}
 	}
 }
 
@@ -309,6 +511,40 @@ func (tn *Alpha) insertChildAtIndex(i int, node *Alpha) {
 	tn.children[i] = node
 }
 
+func (tn *Alpha) removeChildAtIndex(i int) {
+	copy(tn.children[i:], tn.children[i+1:])
+	tn.children[len(tn.children)-1] = nil
+	tn.children = tn.children[:len(tn.children)-1]
+}
+
+// mergeOnlyChild merges a non-root node with its only child, unless that child
+// holds the node's value, so that no node other than the root node has a
+// single child that is not a value node. It returns true when the node was
+// merged.
+func (tn *Alpha) mergeOnlyChild() bool {
+	if len(tn.children) != 1 || tn.children[0].prefix == "" {
+		return false
+	}
+	child := tn.children[0]
+	tn.prefix += child.prefix
+	tn.children = child.children
+	return true
+}
+
+// traceMergeOnlyChild merges a non-root node with its only child, like
+// mergeOnlyChild, and sends a TraceMerge event to tracer when it does. It
+// returns true when the node was merged.
+func (tn *Alpha) traceMergeOnlyChild(tracer Tracer, op string) bool {
+	prefix := tn.prefix
+	if !tn.mergeOnlyChild() {
+		return false
+	}
+	if tracer != nil {
+		tracer.Trace(TraceEvent{Op: op, Kind: TraceMerge, Prefix: prefix})
+	}
+	return true
+}
+
 // Bytes returns a slice of bytes representing a hierarchical display of the
 // Trie.
 func (tn *Alpha) Bytes() []byte {
@@ -333,7 +569,7 @@ func (tn *Alpha) bytes(level int, last bool, bb *bytes.Buffer) {
 	bb.Write([]byte(indention))
 	switch tn.prefix {
 	case "":
-		bb.Write([]byte(fmt.Sprintf(". = %v\n", tn.value)))
+		bb.Write([]byte(fmt.Sprintf(". = %v\n", tn.nodeValue())))
 	default:
 		bb.Write(append([]byte(tn.prefix), '\n'))
 	}
@@ -342,6 +578,15 @@ func (tn *Alpha) bytes(level int, last bool, bb *bytes.Buffer) {
 	}
 }
 
+// nodeValue returns the value of the node, or nil for the root node, which keeps
+// its header in place of a value.
+func (tn *Alpha) nodeValue() interface{} {
+	if _, ok := tn.value.(*alphaHeader); ok {
+		return nil
+	}
+	return tn.value
+}
+
 // Display writes a hierarchical display of the Trie to the specified io.Writer.
 func (tn *Alpha) Display(w io.Writer) {
 	tn.display(w, 0)
@@ -353,7 +598,7 @@ func (tn *Alpha) display(w io.Writer, level int) {
 		indentation += "    "
 	}
 	if tn.prefix == "" {
-		w.Write([]byte(fmt.Sprintf("%s %v\n", indentation, tn.value)))
+		w.Write([]byte(fmt.Sprintf("%s %v\n", indentation, tn.nodeValue())))
 	} else {
 		w.Write([]byte(fmt.Sprintf("%s%q\n", indentation, tn.prefix)))
 	}
@@ -362,46 +607,52 @@ func (tn *Alpha) display(w io.Writer, level int) {
 	}
 }
 
-// Keys returns a list of strings with the specified prefix, to a max of
-
 // Keys returns a slice of keys strings from the Trie with the specified prefix,
 // with no more strings than the specified limit. An empty prefix string matches
 // all Trie keys. A limit of 0 returns all matching keys, not just the first N
-// found.
+// found. Keys are returned in ascending order.
 func (tn *Alpha) Keys(prefix string, limit int) []string {
-	const debug = true
+	fold := tn.folder()
+	if fold != nil {
+		prefix = fold(prefix)
+	}
 
-	curr, extra := tn.keysFindStartingNode(prefix)
+	curr, extra := tn.keysFindStartingNode(prefix, tn.tracer())
 	if curr == nil {
-		if debug {
-			log.Printf("no starting node found")
-		}
 		return nil
 	}
-	if debug {
-		log.Printf("starting node: %+v", curr)
-	}
-	return curr.keysCollectDescendants(prefix, extra, limit)
+
+	// There may be extra characters on the starting node's prefix that we want
+	// to add to all of the descendants below.
+	prefix += curr.prefix[len(curr.prefix)-extra:]
+
+	var list []string
+	curr.walk(prefix, func(key string, value interface{}) bool {
+		if fold != nil {
+			key = value.(*foldedValue).key
+		}
+		list = append(list, key)
+		return limit <= 0 || len(list) < limit
+	})
+	return list
 }
 
 // keysFindStartingNode returns the Trie element that matches the specified
-// prefix.
-func (tn *Alpha) keysFindStartingNode(prefix string) (*Alpha, int) {
-	const debug = false
+// prefix, along with the number of bytes of its prefix that extend beyond the
+// specified prefix. When tracer is not nil, it is sent the steps of the search
+// as those of a Keys operation.
+func (tn *Alpha) keysFindStartingNode(prefix string, tracer Tracer) (*Alpha, int) {
 	curr := tn
 
 	// goal is to eat up the prefix key
 
 	for {
-		if debug {
-			log.Printf("looking for %q at %+v", prefix, curr)
-		}
-
 		i := offsetOfMismatch(prefix, curr.prefix)
 
 		if i == len(prefix) {
-			if debug {
-				log.Printf("consumed all of the prefix: %+v", curr)
+			// consumed all of the prefix
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Keys", Kind: TraceFound, Prefix: curr.prefix})
 			}
 			return curr, len(curr.prefix) - i
 		}
@@ -409,8 +660,8 @@ func (tn *Alpha) keysFindStartingNode(prefix string) (*Alpha, int) {
 		// if more prefix left over, and only portion of curr.prefix matched,
 		// then there are no matches for this prefix.
 		if i < len(curr.prefix) {
-			if debug {
-				log.Printf("??? nothing will match this prefix: %q", prefix)
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Keys", Kind: TraceMiss, Key: prefix, Prefix: curr.prefix})
 			}
 			return nil, 0
 		}
@@ -418,54 +669,101 @@ func (tn *Alpha) keysFindStartingNode(prefix string) (*Alpha, int) {
 		// check children there's more of the prefix to match on
 		suffix := prefix[i:]
 		i = curr.searchChildren(suffix)
-		if debug {
-			log.Printf("going to child: %d", i)
+		if i == len(curr.children) || curr.children[i].prefix == "" {
+			// Value nodes have no descendants to match the remaining prefix.
+			if tracer != nil {
+				tracer.Trace(TraceEvent{Op: "Keys", Kind: TraceMiss, Key: suffix, Prefix: curr.prefix})
+			}
+			return nil, 0
 		}
 		curr = curr.children[i]
 		prefix = suffix
+		if tracer != nil {
+			tracer.Trace(TraceEvent{Op: "Keys", Kind: TraceDescend, Key: prefix, Prefix: curr.prefix})
+		}
 	}
 }
 
-func (tn *Alpha) keysCollectDescendants(prefix string, extra, limit int) []string {
-	const debug = true
-	var list []string
-
-	// There may be extra characters on this node's prefix that we want to add
-	// to all of the descendants below.
-	if extra > 0 { // guard used for performance reason only.
-		prefix += tn.prefix[len(tn.prefix)-extra:]
-	}
-	if debug {
-		log.Printf("prefix: %q", prefix)
-		log.Printf("collect descendants of %+v", tn)
+// keyCount returns the number of key value pairs stored below tn.
+func (tn *Alpha) keyCount() int {
+	var count int
+	for _, child := range tn.children {
+		if child.prefix == "" {
+			count++
+			continue
+		}
+		count += child.keyCount()
 	}
+	return count
+}
 
+// walk invokes fn for every key value pair stored below tn, in ascending key
+// order, where prefix is the key that leads to tn. It stops and returns false as
+// soon as fn returns false.
+func (tn *Alpha) walk(prefix string, fn func(key string, value interface{}) bool) bool {
 	for _, child := range tn.children {
-		if debug {
-			log.Printf("child: %+v", child)
-		}
 		if child.prefix == "" {
-			if debug {
-				log.Printf("adding node: %q", prefix)
-			}
-			list = append(list, "")
-			if limit > 0 && len(list) == limit {
-				return list
+			if !fn(prefix, child.value) {
+				return false
 			}
 			continue // data node does not have children
 		}
-		for _, item := range child.keysCollectDescendants(child.prefix, 0, limit) {
-			if debug {
-				log.Printf("item: %v", item)
+		if !child.walk(prefix+child.prefix, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// walkDelimited is like walk, except that once a key extends beyond its first
+// offset bytes to include the specified delimiter, fn is invoked only once for
+// all of the keys that share that key up to and including the first such
+// delimiter, with that shared key and isPrefix set to true, and the nodes below
+// it are not visited. When after is not empty, nodes below which every key
+// sorts before after are not visited, although fn may still be invoked with
+// keys that do not sort after it.
+func (tn *Alpha) walkDelimited(prefix string, offset int, delimiter, after string, fn func(key string, value interface{}, isPrefix bool) bool) bool {
+	for _, child := range tn.children {
+		if child.prefix == "" {
+			if !fn(prefix, child.value, false) {
+				return false
 			}
-			list = append(list, prefix+child.prefix+item)
-			if limit > 0 && len(list) >= limit {
-				return list[:limit]
+			continue // data node does not have children
+		}
+		key := prefix + child.prefix
+		if after != "" && key < after && !strings.HasPrefix(after, key) {
+			continue // every key below child sorts before after
+		}
+		if i := indexDelimiter(key, offset, len(prefix), delimiter); i >= 0 {
+			if !fn(key[:i+len(delimiter)], nil, true) {
+				return false
 			}
+			continue
 		}
-		if debug {
-			log.Printf("list: %v", list)
+		if !child.walkDelimited(key, offset, delimiter, after, fn) {
+			return false
 		}
 	}
-	return list
+	return true
+}
+
+// indexDelimiter returns the index of the first instance of delimiter in key
+// that starts at or after offset, or -1 when there is none. Because the first
+// known bytes of key have already been searched, only the bytes that may
+// complete a delimiter are searched.
+func indexDelimiter(key string, offset, known int, delimiter string) int {
+	if delimiter == "" {
+		return -1
+	}
+	start := known - len(delimiter) + 1
+	if start < offset {
+		start = offset
+	}
+	if start > len(key) {
+		return -1
+	}
+	if i := strings.Index(key[start:], delimiter); i >= 0 {
+		return start + i
+	}
+	return -1
 }
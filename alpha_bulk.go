@@ -0,0 +1,114 @@
+package goradix
+
+import (
+	"errors"
+	"fmt"
+)
+
+// BulkLoad stores every key value pair returned by iter, which must return its
+// keys in strictly ascending order, and which returns false for ok after its
+// final pair. For a folding Trie, the keys must be in strictly ascending order
+// after they are folded.
+//
+// Rather than descending from the root node for each key, BulkLoad builds the
+// Trie from the bottom up in a single pass, allocating the children of each
+// node once they are all known, with the exact size needed. The Trie must be
+// empty. When BulkLoad returns an error, the Trie is left unchanged.
+func (tn *Alpha) BulkLoad(iter func() (key string, value interface{}, ok bool)) error {
+	if len(tn.children) > 0 || tn.prefix != "" {
+		return errors.New("cannot bulk load: trie not empty")
+	}
+
+	// Each open node is on the path to the previous key, and will not have
+	// its children allocated until no more keys can be stored below it. The
+	// children of all open nodes are kept together in pending, where the
+	// children of each open node start at its base.
+	type openNode struct {
+		node       *Alpha
+		start, end int // offsets into key of first byte and beyond last byte of prefix
+		base       int // index into pending of its first child
+	}
+	var pending []*Alpha
+	stack := []openNode{{}} // the root node is never closed
+
+	// closeTop allocates the children of the top open node, and makes it a
+	// pending child of the open node below it.
+	closeTop := func() {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		top.node.children = make([]*Alpha, len(pending)-top.base)
+		copy(top.node.children, pending[top.base:])
+		for i := top.base; i < len(pending); i++ {
+			pending[i] = nil // allow garbage collection of nodes once loaded
+		}
+		pending = append(pending[:top.base], top.node)
+	}
+
+	fold := tn.folder()
+	var previous string
+	var count int
+
+	for {
+		key, value, ok := iter()
+		if !ok {
+			break
+		}
+		if fold != nil {
+			value = &foldedValue{key: key, value: value}
+			key = fold(key)
+		}
+		if count > 0 {
+			if key == previous {
+				return fmt.Errorf("cannot bulk load key %q: duplicate key", key)
+			}
+			if key < previous {
+				return fmt.Errorf("cannot bulk load key %q: not after previous key %q", key, previous)
+			}
+		}
+		count++
+
+		shared := offsetOfMismatch(previous, key)
+
+		// No more keys can be stored below open nodes whose prefixes start
+		// at or beyond the first byte that differs from the previous key.
+		for len(stack) > 1 && stack[len(stack)-1].start >= shared {
+			closeTop()
+		}
+
+		if top := stack[len(stack)-1]; top.end > shared {
+			// "sally" --> "sam", shared will be "sa"; the open node for
+			// "lly" closes below a new open node for "sa".
+			offset := shared - top.start
+			stack[len(stack)-1] = openNode{
+				node:  &Alpha{prefix: top.node.prefix[:offset]},
+				start: top.start,
+				end:   shared,
+				base:  top.base,
+			}
+			top.node.prefix = top.node.prefix[offset:]
+			top.start = shared
+			stack = append(stack, top)
+			closeTop()
+		}
+
+		if shared < len(key) {
+			stack = append(stack, openNode{
+				node:  &Alpha{prefix: key[shared:]},
+				start: shared,
+				end:   len(key),
+				base:  len(pending),
+			})
+		}
+		pending = append(pending, &Alpha{value: value})
+		previous = key
+	}
+
+	for len(stack) > 1 {
+		closeTop()
+	}
+	if len(pending) > 0 {
+		tn.children = make([]*Alpha, len(pending))
+		copy(tn.children, pending)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package goradix
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// sliceIter returns an iterator over the specified keys, using each key's index
+// as its value.
+func sliceIter(keys []string) func() (string, interface{}, bool) {
+	var i int
+	return func() (string, interface{}, bool) {
+		if i == len(keys) {
+			return "", nil, false
+		}
+		i++
+		return keys[i-1], i - 1, true
+	}
+}
+
+// checkExactChildren fails the test when any node below tn has spare capacity
+// in its children slice.
+func checkExactChildren(t *testing.T, tn *Alpha) {
+	t.Helper()
+	if len(tn.children) != cap(tn.children) {
+		t.Errorf("%q: len: %d; cap: %d", tn.prefix, len(tn.children), cap(tn.children))
+	}
+	for _, child := range tn.children {
+		checkExactChildren(t, child)
+	}
+}
+
+func TestAlphaBulkLoad(t *testing.T) {
+	cases := [][]string{
+		nil,
+		{""},
+		{"", "a"},
+		{"sally", "sam"},
+		{"rob", "robert", "roberta", "roberto", "sam", "samantha", "samuel"},
+		{"a", "ab", "abc", "abd", "abde", "b", "ba", "bb"},
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		keys := randomAlphaKeys(r, r.Intn(40))
+		sort.Strings(keys)
+		var j int
+		for k, key := range keys {
+			if k == 0 || key != keys[j-1] {
+				keys[j] = key
+				j++
+			}
+		}
+		cases = append(cases, keys[:j])
+	}
+
+	for _, keys := range cases {
+		t.Run(fmt.Sprintf("%q", keys), func(t *testing.T) {
+			want := new(Alpha)
+			for i, key := range keys {
+				want.Store(key, i)
+			}
+
+			got := new(Alpha)
+			if err := got.BulkLoad(sliceIter(keys)); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("\nGOT:\n%s\nWANT:\n%s", got.Bytes(), want.Bytes())
+			}
+			checkExactChildren(t, got)
+		})
+	}
+}
+
+func TestAlphaBulkLoadErrors(t *testing.T) {
+	t.Run("Duplicate", func(t *testing.T) {
+		root := new(Alpha)
+		if err := root.BulkLoad(sliceIter([]string{"a", "b", "b"})); err == nil {
+			t.Errorf("GOT: %v; WANT: error", err)
+		}
+		if got, want := len(root.children), 0; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	})
+
+	t.Run("OutOfOrder", func(t *testing.T) {
+		root := new(Alpha)
+		if err := root.BulkLoad(sliceIter([]string{"b", "ab"})); err == nil {
+			t.Errorf("GOT: %v; WANT: error", err)
+		}
+	})
+
+	t.Run("NotEmpty", func(t *testing.T) {
+		root := new(Alpha)
+		root.Store("a", 1)
+		if err := root.BulkLoad(sliceIter([]string{"b"})); err == nil {
+			t.Errorf("GOT: %v; WANT: error", err)
+		}
+	})
+}
+
+func TestFoldingAlphaBulkLoad(t *testing.T) {
+	root := NewFoldingAlpha(FoldCase)
+	if err := root.BulkLoad(sliceIter([]string{"Robert", "sally", "SAM"})); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := root.Keys("", 0), []string{"Robert", "sally", "SAM"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+	value, _ := root.Load("sam")
+	if got, want := value, 2; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	if err := NewFoldingAlpha(FoldCase).BulkLoad(sliceIter([]string{"Sam", "sam"})); err == nil {
+		t.Errorf("GOT: %v; WANT: error", err)
+	}
+}
+
+func benchmarkSortedKeys() []string {
+	keys := make([]string, 100000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("/objects/%08d/metadata", i*7)
+	}
+	return keys
+}
+
+func BenchmarkAlphaStoreSorted(b *testing.B) {
+	keys := benchmarkSortedKeys()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		root := new(Alpha)
+		for j, key := range keys {
+			root.Store(key, j)
+		}
+	}
+}
+
+func BenchmarkAlphaBulkLoad(b *testing.B) {
+	keys := benchmarkSortedKeys()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		root := new(Alpha)
+		if err := root.BulkLoad(sliceIter(keys)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
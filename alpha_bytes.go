@@ -0,0 +1,59 @@
+package goradix
+
+import "unsafe"
+
+// unsafeString returns a string that shares its storage with the specified byte
+// slice. The string must not be retained beyond the call that uses it, and the
+// bytes must not be modified while the string is in use.
+func unsafeString(b []byte) string {
+	return *(*string)(unsafe.Pointer(&b))
+}
+
+// cloneString returns a copy of the specified string that does not share its
+// storage with the original.
+func cloneString(s string) string {
+	b := make([]byte, len(s))
+	copy(b, s)
+	return unsafeString(b)
+}
+
+// DeleteBytes removes the specified key value pair from the Trie. It does not
+// allocate, except when removing the key causes two nodes to be merged.
+func (tn *Alpha) DeleteBytes(key []byte) {
+	tn.Delete(unsafeString(key))
+}
+
+// LoadBytes returns the value associated with the specified key, along with a
+// boolean which is true when the trie has the specified key. It does not
+// allocate, unless the Trie folds keys and its fold function allocates.
+func (tn *Alpha) LoadBytes(key []byte) (interface{}, bool) {
+	return tn.Load(unsafeString(key))
+}
+
+// LongestPrefixBytes returns the longest prefix of the specified key that is
+// stored in the Trie, along with its value and a boolean which is true when such
+// a key was found. The returned slice shares its storage with key. For a folding
+// Trie, the key is returned as it was spelled when it was stored, in a newly
+// allocated slice.
+func (tn *Alpha) LongestPrefixBytes(key []byte) ([]byte, interface{}, bool) {
+	if tn.folder() != nil {
+		original, value, ok := tn.LongestPrefix(unsafeString(key))
+		if !ok {
+			return nil, nil, false
+		}
+		return []byte(original), value, true
+	}
+	length, node := tn.longestPrefix(unsafeString(key), nil)
+	if node == nil {
+		return nil, nil, false
+	}
+	return key[:length], node.value, true
+}
+
+// StoreBytes stores the specified key and value in the Trie. The Trie does not
+// retain key, so the client may reuse it after StoreBytes returns. Only the
+// portion of key that is not already present in the Trie is copied, so updating
+// the value of an existing key does not allocate.
+func (tn *Alpha) StoreBytes(key []byte, value interface{}) {
+	tn.store(unsafeString(key), value, true)
+}
@@ -0,0 +1,164 @@
+package goradix
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestAlphaBytes(t *testing.T) {
+	root := new(Alpha)
+
+	key := []byte("samuel")
+	root.StoreBytes(key, 1)
+	copy(key, "robert") // trie must not retain client's bytes
+	root.StoreBytes(key, 2)
+	root.StoreBytes([]byte("sam"), 3)
+
+	t.Run("LoadBytes", func(t *testing.T) {
+		value, ok := root.LoadBytes([]byte("samuel"))
+		if got, want := ok, true; got != want {
+			t.Fatalf("GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := value, 1; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+
+		value, ok = root.LoadBytes([]byte("robert"))
+		if got, want := ok, true; got != want {
+			t.Fatalf("GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := value, 2; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+
+		_, ok = root.LoadBytes([]byte("sally"))
+		if got, want := ok, false; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	})
+
+	t.Run("LongestPrefixBytes", func(t *testing.T) {
+		prefix, value, ok := root.LongestPrefixBytes([]byte("samuelson"))
+		if got, want := ok, true; got != want {
+			t.Fatalf("GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := string(prefix), "samuel"; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := value, 1; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+
+		prefix, value, ok = root.LongestPrefixBytes([]byte("samantha"))
+		if got, want := ok, true; got != want {
+			t.Fatalf("GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := string(prefix), "sam"; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := value, 3; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+
+		_, _, ok = root.LongestPrefixBytes([]byte("sa"))
+		if got, want := ok, false; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	})
+
+	t.Run("DeleteBytes", func(t *testing.T) {
+		root := new(Alpha)
+		root.StoreBytes([]byte("roberta"), 1)
+		root.StoreBytes([]byte("roberto"), 2)
+
+		root.DeleteBytes([]byte("roberta"))
+
+		_, ok := root.LoadBytes([]byte("roberta"))
+		if got, want := ok, false; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+		_, ok = root.LoadBytes([]byte("roberto"))
+		if got, want := ok, true; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	})
+}
+
+func TestAlphaBytesDoesNotAllocate(t *testing.T) {
+	root := new(Alpha)
+	root.Store("/Aaron/Dabih/Cabinda", nil)
+	root.Store("/Baalath/Dabih/Cabinda", nil)
+	root.Store("/Cabinda/Earle/Dabih", nil)
+
+	key := []byte("/Baalath/Dabih/Cabinda")
+	missing := []byte("/Baalath/Dabih/Earle")
+	longer := []byte("/Cabinda/Earle/Dabih/Aaron")
+
+	cases := []struct {
+		name string
+		fn   func()
+	}{
+		{"LoadBytes", func() { root.LoadBytes(key) }},
+		{"LoadBytesMissing", func() { root.LoadBytes(missing) }},
+		{"LongestPrefixBytes", func() { root.LongestPrefixBytes(longer) }},
+		{"StoreBytesExisting", func() { root.StoreBytes(key, nil) }},
+		{"DeleteBytesMissing", func() { root.DeleteBytes(missing) }},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			if got, want := testing.AllocsPerRun(100, item.fn), 0.0; got != want {
+				t.Errorf("GOT: %v; WANT: %v", got, want)
+			}
+		})
+	}
+}
+
+func benchmarkAlphaBytesTrie() (*Alpha, [][]byte) {
+	root := new(Alpha)
+	keys := make([][]byte, 1000)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("/objects/%08x/metadata", i*7919))
+		root.StoreBytes(keys[i], nil)
+	}
+	return root, keys
+}
+
+func BenchmarkAlphaLoad(b *testing.B) {
+	root, keys := benchmarkAlphaBytesTrie()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = root.Load(string(keys[i%len(keys)]))
+	}
+}
+
+func BenchmarkAlphaLoadBytes(b *testing.B) {
+	root, keys := benchmarkAlphaBytesTrie()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = root.LoadBytes(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkAlphaStoreBytes(b *testing.B) {
+	root, keys := benchmarkAlphaBytesTrie()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		root.StoreBytes(keys[i%len(keys)], nil)
+	}
+}
+
+func BenchmarkAlphaLongestPrefixBytes(b *testing.B) {
+	root, keys := benchmarkAlphaBytesTrie()
+	for i := range keys {
+		keys[i] = append(keys[i], "/etag"...)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = root.LongestPrefixBytes(keys[i%len(keys)])
+	}
+}
@@ -0,0 +1,65 @@
+package goradix
+
+import "reflect"
+
+// Clone returns a deep copy of the Trie. The values themselves are not copied,
+// so the copy refers to the same values as the original.
+func (tn *Alpha) Clone() *Alpha {
+	return tn.CloneFunc(nil)
+}
+
+// CloneFunc returns a deep copy of the Trie, where each value is copied by
+// invoking copyValue with the original value. When copyValue is nil, the copy
+// refers to the same values as the original. The copy has the same Tracer and
+// Observer as the original, which may be changed without affecting the
+// original.
+func (tn *Alpha) CloneFunc(copyValue func(value interface{}) interface{}) *Alpha {
+	if tn.folder() != nil && copyValue != nil {
+		copyClientValue := copyValue
+		copyValue = func(value interface{}) interface{} {
+			fv := value.(*foldedValue)
+			return &foldedValue{key: fv.key, value: copyClientValue(fv.value)}
+		}
+	}
+	root := &Alpha{prefix: tn.prefix}
+	if h := tn.header(); h != nil {
+		header := *h
+		root.value = &header
+	}
+	if len(tn.children) > 0 {
+		root.children = make([]*Alpha, len(tn.children))
+		for i, child := range tn.children {
+			root.children[i] = child.cloneFrom(0, copyValue)
+		}
+	}
+	return root
+}
+
+// Equal returns true when the Trie has the same keys as the other Trie, and the
+// values for each key are equal according to eq. When eq is nil,
+// reflect.DeepEqual is used. Tries are compared by their contents, so two Tries
+// that were built by storing the same keys in different orders are equal. For
+// folding Tries, which must fold keys the same way, keys are compared after
+// they are folded. A nil Trie is equal to an empty Trie.
+func (tn *Alpha) Equal(other *Alpha, eq func(a, b interface{}) bool) bool {
+	if tn == nil {
+		tn = new(Alpha)
+	}
+	if other == nil {
+		other = new(Alpha)
+	}
+	if eq == nil {
+		eq = reflect.DeepEqual
+	}
+	equal := true
+	d := &alphaDiff{
+		fold:  tn.folder() != nil || other.folder() != nil,
+		equal: eq,
+		fn: func(string, DiffKind, interface{}, interface{}) bool {
+			equal = false
+			return false
+		},
+	}
+	d.spans("", alphaSpanOf(tn), alphaSpanOf(other))
+	return equal
+}
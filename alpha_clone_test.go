@@ -0,0 +1,127 @@
+package goradix
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAlphaClone(t *testing.T) {
+	trie := new(Alpha)
+	trie.Store("", 0)
+	trie.Store("roberta", 1)
+	trie.Store("roberto", 2)
+	trie.Store("sam", 3)
+
+	clone := trie.Clone()
+	if !trie.Equal(clone, nil) {
+		t.Fatalf("GOT: %s; WANT: %s", clone.Bytes(), trie.Bytes())
+	}
+
+	// Changing the clone must not change the original.
+	clone.Store("roberta", 10)
+	clone.Delete("sam")
+	clone.Store("samuel", 4)
+
+	if value, ok := trie.Load("roberta"); !ok || value != 1 {
+		t.Errorf("GOT: %v, %v; WANT: %v, %v", value, ok, 1, true)
+	}
+	if value, ok := trie.Load("sam"); !ok || value != 3 {
+		t.Errorf("GOT: %v, %v; WANT: %v, %v", value, ok, 3, true)
+	}
+	if _, ok := trie.Load("samuel"); ok {
+		t.Errorf("GOT: %v; WANT: %v", ok, false)
+	}
+	if trie.Equal(clone, nil) {
+		t.Errorf("GOT: %v; WANT: %v", true, false)
+	}
+}
+
+func TestAlphaCloneFunc(t *testing.T) {
+	trie := new(Alpha)
+	trie.Store("a", []int{1})
+	trie.Store("ab", []int{2})
+
+	clone := trie.CloneFunc(func(value interface{}) interface{} {
+		return append([]int(nil), value.([]int)...)
+	})
+
+	value, _ := clone.Load("ab")
+	value.([]int)[0] = 20
+
+	if value, _ := trie.Load("ab"); value.([]int)[0] != 2 {
+		t.Errorf("GOT: %v; WANT: %v", value, []int{2})
+	}
+}
+
+func TestAlphaCloneFolding(t *testing.T) {
+	trie := NewFoldingAlpha(FoldCase)
+	trie.Store("Sam", 1)
+
+	clone := trie.CloneFunc(func(value interface{}) interface{} {
+		return value.(int) * 10
+	})
+	clone.Store("ROBERT", 2)
+
+	if value, ok := clone.Load("sam"); !ok || value != 10 {
+		t.Errorf("GOT: %v, %v; WANT: %v, %v", value, ok, 10, true)
+	}
+	if value, ok := clone.Load("robert"); !ok || value != 2 {
+		t.Errorf("GOT: %v, %v; WANT: %v, %v", value, ok, 2, true)
+	}
+	if _, ok := trie.Load("robert"); ok {
+		t.Errorf("GOT: %v; WANT: %v", ok, false)
+	}
+}
+
+func TestAlphaEqualInsertionOrder(t *testing.T) {
+	keys := []string{"romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "rom", "r"}
+
+	first, second := new(Alpha), new(Alpha)
+	for i, key := range keys {
+		first.Store(key, i)
+	}
+	for i := len(keys) - 1; i >= 0; i-- {
+		second.Store(keys[i], i)
+	}
+	// Leave a different internal layout behind in one of the Tries.
+	second.Store("rubberband", -1)
+	second.Delete("rubberband")
+
+	if !first.Equal(second, nil) {
+		t.Errorf("GOT: %s; WANT: %s", second.Bytes(), first.Bytes())
+	}
+
+	second.Store("rom", -1)
+	if first.Equal(second, nil) {
+		t.Errorf("GOT: %v; WANT: %v", true, false)
+	}
+}
+
+func TestAlphaEqualFunc(t *testing.T) {
+	first, second := new(Alpha), new(Alpha)
+	first.Store("a", "hello")
+	second.Store("a", "HELLO")
+
+	if first.Equal(second, nil) {
+		t.Errorf("GOT: %v; WANT: %v", true, false)
+	}
+
+	eq := func(a, b interface{}) bool {
+		return strings.EqualFold(a.(string), b.(string))
+	}
+	if !first.Equal(second, eq) {
+		t.Errorf("GOT: %v; WANT: %v", false, true)
+	}
+}
+
+func TestAlphaEqualNil(t *testing.T) {
+	trie := new(Alpha)
+	if !trie.Equal(nil, nil) {
+		t.Errorf("GOT: %v; WANT: %v", false, true)
+	}
+
+	trie.Store("a", 1)
+	if trie.Equal(nil, nil) {
+		t.Errorf("GOT: %v; WANT: %v", true, false)
+	}
+}
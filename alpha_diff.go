@@ -0,0 +1,140 @@
+package goradix
+
+import "reflect"
+
+// DiffKind describes how the value of a key differs between two Tries.
+type DiffKind int
+
+const (
+	// DiffAdded means the key is only in the new Trie.
+	DiffAdded DiffKind = iota
+
+	// DiffRemoved means the key is only in the old Trie.
+	DiffRemoved
+
+	// DiffChanged means the key is in both Tries, with different values.
+	DiffChanged
+)
+
+func (k DiffKind) String() string {
+	switch k {
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffChanged:
+		return "changed"
+	default:
+		return "unknown"
+	}
+}
+
+// Diff invokes fn, in ascending key order, for each key that was added to,
+// removed from, or changed between the old Trie and the new Trie. The value of
+// a key that is only in one of the Tries is nil for the other Trie. Two values
+// are the same when reflect.DeepEqual returns true for them. Diff stops when fn
+// returns false. Either Trie may be nil, which is treated like an empty Trie.
+//
+// Both Tries are walked together in sorted order, so only the parts of the
+// Tries that differ are visited. When both Tries share a node, such as when one
+// was derived from the other without copying that node, the entire subtree
+// below that node is skipped. For folding Tries, which must fold keys the same
+// way, fn is invoked with the keys as they were spelled when they were stored.
+func Diff(oldTrie, newTrie *Alpha, fn func(key string, kind DiffKind, oldValue, newValue interface{}) bool) {
+	if oldTrie == nil {
+		oldTrie = new(Alpha)
+	}
+	if newTrie == nil {
+		newTrie = new(Alpha)
+	}
+	d := &alphaDiff{fold: oldTrie.folder() != nil || newTrie.folder() != nil, equal: reflect.DeepEqual, fn: fn}
+	d.spans("", alphaSpanOf(oldTrie), alphaSpanOf(newTrie))
+}
+
+type alphaDiff struct {
+	fold  bool // values are stored as *foldedValue
+	equal func(a, b interface{}) bool
+	fn    func(key string, kind DiffKind, oldValue, newValue interface{}) bool
+}
+
+// spans compares the edges that leave the two specified positions, where key
+// is the key that leads to both positions. It returns false when fn asked to
+// stop.
+func (d *alphaDiff) spans(key string, as, bs alphaSpan) bool {
+	var i, j int
+	na, nb := as.len(), bs.len()
+
+	for i < na || j < nb {
+		var a, b *Alpha
+		var askip, bskip int
+		if i < na {
+			a, askip = as.edge(i)
+		}
+		if j < nb {
+			b, bskip = bs.edge(j)
+		}
+
+		switch c := compareEdges(a, askip, b, bskip); {
+		case c < 0:
+			if !d.only(key, a, askip, DiffRemoved) {
+				return false
+			}
+			i++
+		case c > 0:
+			if !d.only(key, b, bskip, DiffAdded) {
+				return false
+			}
+			j++
+		default:
+			if !d.both(key, a, askip, b, bskip) {
+				return false
+			}
+			i++
+			j++
+		}
+	}
+
+	return true
+}
+
+// only reports every key below an edge that is only in one of the Tries.
+func (d *alphaDiff) only(key string, node *Alpha, skip int, kind DiffKind) bool {
+	report := func(key string, value interface{}) bool {
+		if d.fold {
+			fv := value.(*foldedValue)
+			key, value = fv.key, fv.value
+		}
+		if kind == DiffAdded {
+			return d.fn(key, kind, nil, value)
+		}
+		return d.fn(key, kind, value, nil)
+	}
+	if node.prefix == "" {
+		return report(key, node.value)
+	}
+	return node.walk(key+node.prefix[skip:], report)
+}
+
+// both compares two edges that begin with the same byte.
+func (d *alphaDiff) both(key string, a *Alpha, askip int, b *Alpha, bskip int) bool {
+	if a == b && askip == bskip {
+		return true // shared subtree is identical
+	}
+
+	pa, pb := a.prefix[askip:], b.prefix[bskip:]
+
+	if pa == "" { // both are value nodes
+		oldValue, newValue := a.value, b.value
+		if d.fold {
+			fa, fb := oldValue.(*foldedValue), newValue.(*foldedValue)
+			key, oldValue, newValue = fb.key, fa.value, fb.value
+		}
+		if d.equal(oldValue, newValue) {
+			return true
+		}
+		return d.fn(key, DiffChanged, oldValue, newValue)
+	}
+
+	shared := offsetOfMismatch(pa, pb)
+	return d.spans(key+pa[:shared], alphaSpan{a, askip + shared}, alphaSpan{b, bskip + shared})
+}
@@ -0,0 +1,132 @@
+package goradix
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	oldTrie, newTrie := new(Alpha), new(Alpha)
+	oldTrie.Store("roberta", 1)
+	oldTrie.Store("roberto", 2)
+	oldTrie.Store("sam", 3)
+	oldTrie.Store("samuel", 4)
+
+	newTrie.Store("robert", 5)
+	newTrie.Store("roberto", 2)
+	newTrie.Store("sam", 6)
+	newTrie.Store("samantha", 7)
+
+	var got []string
+	Diff(oldTrie, newTrie, func(key string, kind DiffKind, oldValue, newValue interface{}) bool {
+		got = append(got, fmt.Sprintf("%s %s %v %v", kind, key, oldValue, newValue))
+		return true
+	})
+
+	want := []string{
+		"added robert <nil> 5",
+		"removed roberta 1 <nil>",
+		"changed sam 3 6",
+		"added samantha <nil> 7",
+		"removed samuel 4 <nil>",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nGOT:  %q\nWANT: %q", got, want)
+	}
+}
+
+func TestDiffStops(t *testing.T) {
+	newTrie := new(Alpha)
+	newTrie.Store("a", 1)
+	newTrie.Store("b", 2)
+	newTrie.Store("c", 3)
+
+	var count int
+	Diff(nil, newTrie, func(string, DiffKind, interface{}, interface{}) bool {
+		count++
+		return count < 2
+	})
+
+	if got, want := count, 2; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestDiffSkipsSharedSubtrees(t *testing.T) {
+	// NaN never equals itself, so the key would be reported as changed were
+	// its shared node compared rather than skipped.
+	shared := &Alpha{
+		prefix:   "nan",
+		children: []*Alpha{&Alpha{value: math.NaN()}},
+	}
+	oldTrie := &Alpha{children: []*Alpha{shared}}
+	newTrie := &Alpha{children: []*Alpha{shared}}
+	newTrie.Store("zulu", 1)
+
+	var got []string
+	Diff(oldTrie, newTrie, func(key string, kind DiffKind, _, _ interface{}) bool {
+		got = append(got, kind.String()+" "+key)
+		return true
+	})
+
+	if want := []string{"added zulu"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+}
+
+func TestDiffFolding(t *testing.T) {
+	oldTrie, newTrie := NewFoldingAlpha(FoldCase), NewFoldingAlpha(FoldCase)
+	oldTrie.Store("Sam", 1)
+	newTrie.Store("SAM", 2)
+
+	var got []string
+	Diff(oldTrie, newTrie, func(key string, kind DiffKind, oldValue, newValue interface{}) bool {
+		got = append(got, fmt.Sprintf("%s %s %v %v", kind, key, oldValue, newValue))
+		return true
+	})
+
+	if want := []string{"changed SAM 1 2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+}
+
+func TestDiffRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for iteration := 0; iteration < 200; iteration++ {
+		oldTrie, newTrie := new(Alpha), new(Alpha)
+		for _, key := range randomAlphaKeys(r, r.Intn(30)) {
+			oldTrie.Store(key, r.Intn(3))
+		}
+		for _, key := range randomAlphaKeys(r, r.Intn(30)) {
+			newTrie.Store(key, r.Intn(3))
+		}
+
+		// Applying every reported difference to the old contents must yield the
+		// new contents.
+		contents := alphaContents(oldTrie)
+		var previous string
+		Diff(oldTrie, newTrie, func(key string, kind DiffKind, oldValue, newValue interface{}) bool {
+			if previous != "" && key <= previous {
+				t.Fatalf("keys out of order: %q after %q", key, previous)
+			}
+			previous = key
+			if got, want := oldValue, contents[key]; got != want {
+				t.Fatalf("%s %q; GOT: %v; WANT: %v", kind, key, got, want)
+			}
+			if kind == DiffRemoved {
+				delete(contents, key)
+			} else {
+				contents[key] = newValue
+			}
+			return true
+		})
+
+		if got, want := contents, alphaContents(newTrie); !reflect.DeepEqual(got, want) {
+			t.Fatalf("\nGOT:  %v\nWANT: %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package goradix
+
+import "strings"
+
+// ListEntry is a key value pair returned by Alpha.List.
+type ListEntry struct {
+	Key   string
+	Value interface{}
+}
+
+// ListResult is a single page of results returned by Alpha.List.
+type ListResult struct {
+	// Contents holds the key value pairs that matched the prefix, but not
+	// any of the common prefixes.
+	Contents []ListEntry
+
+	// CommonPrefixes holds each distinct key that matched the prefix, up to
+	// and including the first delimiter after the prefix. Every key that
+	// shares a common prefix is represented only by that common prefix.
+	CommonPrefixes []string
+
+	// IsTruncated is true when more results remain after this page.
+	IsTruncated bool
+
+	// ContinuationToken is not empty when IsTruncated is true. Passing it to
+	// ListContinue returns the next page. It is not a key, so that a page that
+	// ends with the empty key can be told apart from the first page.
+	ContinuationToken string
+}
+
+// listToken starts every ContinuationToken, and is followed by the last key or
+// common prefix in the page.
+const listToken = ">"
+
+// List returns a page of the keys and their values from the Trie with the
+// specified prefix, much like the ListObjectsV2 operation of Amazon S3.
+//
+// When delimiter is not empty, keys that contain the delimiter after the prefix
+// are grouped by the portion of the key up to and including the first such
+// delimiter, and only that common prefix is returned for them. When startAfter
+// is not empty, only keys and common prefixes that sort after it are
+// returned. When maxKeys is greater than 0, no more than maxKeys keys and common
+// prefixes combined are returned, and ListContinue returns the following pages.
+// Keys and common prefixes are visited in ascending order, and subtrees that
+// sort entirely before startAfter are not visited.
+//
+// For a folding Trie, the prefix and startAfter are folded, keys are returned
+// as they were spelled when they were stored, and common prefixes are returned
+// folded.
+func (tn *Alpha) List(prefix, delimiter, startAfter string, maxKeys int) ListResult {
+	if fold := tn.folder(); fold != nil && startAfter != "" {
+		startAfter = fold(startAfter)
+	}
+	return tn.list(prefix, delimiter, startAfter, startAfter != "", maxKeys)
+}
+
+// ListContinue returns the page of results that follows the page whose
+// ContinuationToken is specified, for the same prefix, delimiter and maxKeys.
+// An empty token returns the first page.
+func (tn *Alpha) ListContinue(prefix, delimiter, token string, maxKeys int) ListResult {
+	if !strings.HasPrefix(token, listToken) {
+		return tn.list(prefix, delimiter, "", false, maxKeys)
+	}
+	return tn.list(prefix, delimiter, token[len(listToken):], true, maxKeys)
+}
+
+// list returns a page of results like List, where only the keys and common
+// prefixes that sort after the specified startAfter, which is already folded,
+// are returned when after is true. Unlike for List, an empty startAfter then
+// skips the empty key.
+func (tn *Alpha) list(prefix, delimiter, startAfter string, after bool, maxKeys int) ListResult {
+	var result ListResult
+
+	fold := tn.folder()
+	if fold != nil {
+		prefix = fold(prefix)
+	}
+
+	curr, extra := tn.keysFindStartingNode(prefix, nil)
+	if curr == nil {
+		return result
+	}
+
+	var count int
+	emit := func(key string, value interface{}, isPrefix bool) bool {
+		if after && key <= startAfter {
+			return true
+		}
+		if maxKeys > 0 && count == maxKeys {
+			result.IsTruncated = true
+			return false
+		}
+		if isPrefix {
+			result.CommonPrefixes = append(result.CommonPrefixes, key)
+		} else {
+			entry := ListEntry{Key: key, Value: value}
+			if fold != nil {
+				fv := value.(*foldedValue)
+				entry.Key, entry.Value = fv.key, fv.value
+			}
+			result.Contents = append(result.Contents, entry)
+		}
+		result.ContinuationToken = listToken + key
+		count++
+		return true
+	}
+
+	// There may be extra characters on the starting node's prefix that we want
+	// to add to all of the descendants below.
+	key := prefix + curr.prefix[len(curr.prefix)-extra:]
+
+	if i := indexDelimiter(key, len(prefix), len(prefix), delimiter); i >= 0 {
+		emit(key[:i+len(delimiter)], nil, true)
+	} else {
+		curr.walkDelimited(key, len(prefix), delimiter, startAfter, emit)
+	}
+
+	if !result.IsTruncated {
+		result.ContinuationToken = ""
+	}
+	return result
+}
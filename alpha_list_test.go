@@ -0,0 +1,224 @@
+package goradix
+
+import (
+	"reflect"
+	"testing"
+)
+
+func listKeys(result ListResult) []string {
+	var keys []string
+	for _, entry := range result.Contents {
+		keys = append(keys, entry.Key)
+	}
+	return keys
+}
+
+func TestAlphaList(t *testing.T) {
+	root := new(Alpha)
+	root.Store("index.html", 0)
+	root.Store("photos/2006/January/sample.jpg", 1)
+	root.Store("photos/2006/February/sample2.jpg", 2)
+	root.Store("photos/2006/February/sample3.jpg", 3)
+	root.Store("photos/2006/February/sample4.jpg", 4)
+	root.Store("photos/2007/sample.jpg", 5)
+	root.Store("photos/index.html", 6)
+	root.Store("videos/intro.mp4", 7)
+
+	cases := []struct {
+		name                     string
+		prefix, delimiter, after string
+		keys, commonPrefixes     []string
+	}{
+		{
+			name:           "RootWithDelimiter",
+			delimiter:      "/",
+			keys:           []string{"index.html"},
+			commonPrefixes: []string{"photos/", "videos/"},
+		},
+		{
+			name:           "PrefixWithDelimiter",
+			prefix:         "photos/",
+			delimiter:      "/",
+			keys:           []string{"photos/index.html"},
+			commonPrefixes: []string{"photos/2006/", "photos/2007/"},
+		},
+		{
+			name:           "PartialSegmentPrefix",
+			prefix:         "photos/2006/",
+			delimiter:      "/",
+			commonPrefixes: []string{"photos/2006/February/", "photos/2006/January/"},
+		},
+		{
+			name:           "StartingNodeHasDelimiter",
+			prefix:         "v",
+			delimiter:      "/",
+			commonPrefixes: []string{"videos/"},
+		},
+		{
+			name:   "WithoutDelimiter",
+			prefix: "photos/2006/F",
+			keys: []string{
+				"photos/2006/February/sample2.jpg",
+				"photos/2006/February/sample3.jpg",
+				"photos/2006/February/sample4.jpg",
+			},
+		},
+		{
+			name:           "StartAfterCommonPrefix",
+			delimiter:      "/",
+			after:          "photos/",
+			commonPrefixes: []string{"videos/"},
+		},
+		{
+			name:   "StartAfterKey",
+			prefix: "photos/2006/",
+			after:  "photos/2006/February/sample3.jpg",
+			keys: []string{
+				"photos/2006/February/sample4.jpg",
+				"photos/2006/January/sample.jpg",
+			},
+		},
+		{
+			name:   "NoMatch",
+			prefix: "music/",
+		},
+	}
+
+	for _, item := range cases {
+		t.Run(item.name, func(t *testing.T) {
+			result := root.List(item.prefix, item.delimiter, item.after, 0)
+			if got, want := listKeys(result), item.keys; !reflect.DeepEqual(got, want) {
+				t.Errorf("GOT: %q; WANT: %q", got, want)
+			}
+			if got, want := result.CommonPrefixes, item.commonPrefixes; !reflect.DeepEqual(got, want) {
+				t.Errorf("GOT: %q; WANT: %q", got, want)
+			}
+			if got, want := result.IsTruncated, false; got != want {
+				t.Errorf("GOT: %v; WANT: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestAlphaListValues(t *testing.T) {
+	root := new(Alpha)
+	root.Store("index.html", 0)
+	root.Store("photos/2006/January/sample.jpg", 1)
+	root.Store("photos/2006/February/sample2.jpg", 2)
+	root.Store("photos/2006/February/sample3.jpg", 3)
+	root.Store("photos/2006/February/sample4.jpg", 4)
+	root.Store("photos/2007/sample.jpg", 5)
+	root.Store("photos/index.html", 6)
+	root.Store("videos/intro.mp4", 7)
+
+	result := root.List("photos/2007/", "", "", 0)
+	if got, want := len(result.Contents), 1; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := result.Contents[0].Value, 5; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestAlphaListPaginates(t *testing.T) {
+	root := new(Alpha)
+	root.Store("index.html", 0)
+	root.Store("photos/2006/January/sample.jpg", 1)
+	root.Store("photos/2006/February/sample2.jpg", 2)
+	root.Store("photos/2006/February/sample3.jpg", 3)
+	root.Store("photos/2006/February/sample4.jpg", 4)
+	root.Store("photos/2007/sample.jpg", 5)
+	root.Store("photos/index.html", 6)
+	root.Store("videos/intro.mp4", 7)
+
+	var keys, commonPrefixes []string
+	var pages int
+	var token string
+
+	for {
+		result := root.ListContinue("photos/", "/", token, 2)
+		pages++
+		keys = append(keys, listKeys(result)...)
+		commonPrefixes = append(commonPrefixes, result.CommonPrefixes...)
+		if !result.IsTruncated {
+			if got, want := result.ContinuationToken, ""; got != want {
+				t.Errorf("GOT: %q; WANT: %q", got, want)
+			}
+			break
+		}
+		token = result.ContinuationToken
+	}
+
+	if got, want := pages, 2; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := keys, []string{"photos/index.html"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+	if got, want := commonPrefixes, []string{"photos/2006/", "photos/2007/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+}
+
+func TestAlphaListPaginatesPastEmptyKey(t *testing.T) {
+	root := new(Alpha)
+	root.Store("", 1)
+	root.Store("\x00", 2)
+	root.Store("a", 3)
+
+	var keys []string
+	var token string
+
+	for pages := 1; pages <= 3; pages++ {
+		result := root.ListContinue("", "", token, 1)
+		keys = append(keys, listKeys(result)...)
+		if !result.IsTruncated {
+			break
+		}
+		if got, want := result.ContinuationToken != "", true; got != want {
+			t.Fatalf("GOT: %v; WANT: %v", got, want)
+		}
+		token = result.ContinuationToken
+	}
+
+	if got, want := keys, []string{"", "\x00", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+}
+
+func TestAlphaListExactPageIsNotTruncated(t *testing.T) {
+	root := new(Alpha)
+	root.Store("index.html", 0)
+	root.Store("photos/2006/January/sample.jpg", 1)
+	root.Store("photos/2006/February/sample2.jpg", 2)
+	root.Store("photos/2006/February/sample3.jpg", 3)
+	root.Store("photos/2006/February/sample4.jpg", 4)
+	root.Store("photos/2007/sample.jpg", 5)
+	root.Store("photos/index.html", 6)
+	root.Store("videos/intro.mp4", 7)
+
+	result := root.List("photos/2006/February/", "", "", 3)
+	if got, want := len(result.Contents), 3; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := result.IsTruncated, false; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestFoldingAlphaList(t *testing.T) {
+	root := NewFoldingAlpha(FoldCase)
+	root.Store("Photos/Sample.JPG", 1)
+	root.Store("Photos/2006/sample.jpg", 2)
+
+	result := root.List("PHOTOS/", "/", "", 0)
+	if got, want := listKeys(result), []string{"Photos/Sample.JPG"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+	if got, want := result.Contents[0].Value, 1; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := result.CommonPrefixes, []string{"photos/2006/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+}
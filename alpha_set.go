@@ -0,0 +1,188 @@
+package goradix
+
+// Difference returns a new Trie with the key value pairs from this Trie whose
+// keys are not in the other Trie. Both Tries must fold keys the same way, or
+// not at all.
+func (tn *Alpha) Difference(other *Alpha) *Alpha {
+	op := &alphaSetOp{keepOnlyA: true}
+	return tn.newRoot(op.children("", alphaSpanOf(tn), alphaSpanOf(other)))
+}
+
+// Intersect returns a new Trie with the keys that are in both this Trie and the
+// other Trie. The value for each key is the value returned by resolve, which is
+// invoked with the key and the values from this Trie and the other Trie, in
+// that order. When resolve is nil, the value from this Trie is used. Both Tries
+// must fold keys the same way, or not at all.
+func (tn *Alpha) Intersect(other *Alpha, resolve func(key string, a, b interface{}) interface{}) *Alpha {
+	op := &alphaSetOp{keepBoth: true, fold: tn.folder() != nil, resolve: resolve}
+	return tn.newRoot(op.children("", alphaSpanOf(tn), alphaSpanOf(other)))
+}
+
+// Union returns a new Trie with the keys that are in either this Trie or the
+// other Trie. The value for a key that is in only one of the Tries is the value
+// from that Trie. The value for a key that is in both Tries is the value
+// returned by resolve, which is invoked with the key and the values from this
+// Trie and the other Trie, in that order. When resolve is nil, the value from
+// this Trie is used. Both Tries must fold keys the same way, or not at all.
+func (tn *Alpha) Union(other *Alpha, resolve func(key string, a, b interface{}) interface{}) *Alpha {
+	op := &alphaSetOp{keepOnlyA: true, keepOnlyB: true, keepBoth: true, fold: tn.folder() != nil, resolve: resolve}
+	return tn.newRoot(op.children("", alphaSpanOf(tn), alphaSpanOf(other)))
+}
+
+// alphaSpan is the list of edges that leave a position within a Trie, where
+// the position is skip bytes into the prefix of node. When skip is the length
+// of the prefix, the edges are the children of node. Otherwise there is a
+// single edge, made of the rest of the prefix and the children of node.
+type alphaSpan struct {
+	node *Alpha
+	skip int
+}
+
+func alphaSpanOf(tn *Alpha) alphaSpan {
+	return alphaSpan{node: tn, skip: len(tn.prefix)}
+}
+
+func (s alphaSpan) len() int {
+	if s.skip == len(s.node.prefix) {
+		return len(s.node.children)
+	}
+	return 1
+}
+
+// edge returns the node of the ith edge, along with the number of bytes of its
+// prefix that are not part of the edge.
+func (s alphaSpan) edge(i int) (*Alpha, int) {
+	if s.skip == len(s.node.prefix) {
+		return s.node.children[i], 0
+	}
+	return s.node, s.skip
+}
+
+// compareEdges returns a negative number when edge a sorts before edge b, a
+// positive number when edge b sorts before edge a, and zero when both edges
+// begin with the same byte or both lead to a value. A nil node sorts after all
+// others.
+func compareEdges(a *Alpha, askip int, b *Alpha, bskip int) int {
+	switch {
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	ra, rb := -1, -1 // value nodes sort first
+	if askip < len(a.prefix) {
+		ra = int(a.prefix[askip])
+	}
+	if bskip < len(b.prefix) {
+		rb = int(b.prefix[bskip])
+	}
+	return ra - rb
+}
+
+// alphaSetOp walks two Tries in lockstep, building the children of a new Trie.
+// Subtrees that are only in one of the Tries are either copied or skipped in
+// their entirety, without visiting the keys inside them.
+type alphaSetOp struct {
+	keepOnlyA, keepOnlyB, keepBoth bool
+	fold                           bool // values are stored as *foldedValue
+	resolve                        func(key string, a, b interface{}) interface{}
+}
+
+// children returns the children of a new node for the edges leaving the two
+// specified positions, where key is the key that leads to both positions.
+func (op *alphaSetOp) children(key string, as, bs alphaSpan) []*Alpha {
+	var result []*Alpha
+	var i, j int
+	na, nb := as.len(), bs.len()
+
+	for i < na || j < nb {
+		var a, b *Alpha
+		var askip, bskip int
+		if i < na {
+			a, askip = as.edge(i)
+		}
+		if j < nb {
+			b, bskip = bs.edge(j)
+		}
+
+		switch c := compareEdges(a, askip, b, bskip); {
+		case c < 0:
+			if op.keepOnlyA {
+				result = append(result, a.cloneFrom(askip, nil))
+			}
+			i++
+		case c > 0:
+			if op.keepOnlyB {
+				result = append(result, b.cloneFrom(bskip, nil))
+			}
+			j++
+		default:
+			if node := op.merge(key, a, askip, b, bskip); node != nil {
+				result = append(result, node)
+			}
+			i++
+			j++
+		}
+	}
+
+	return result
+}
+
+// merge returns a new node for two edges that begin with the same byte, or nil
+// when no keys below them remain.
+func (op *alphaSetOp) merge(key string, a *Alpha, askip int, b *Alpha, bskip int) *Alpha {
+	pa, pb := a.prefix[askip:], b.prefix[bskip:]
+
+	if pa == "" { // both are value nodes
+		if !op.keepBoth {
+			return nil
+		}
+		return &Alpha{value: op.resolveValues(key, a.value, b.value)}
+	}
+
+	shared := offsetOfMismatch(pa, pb)
+	prefix := pa[:shared]
+	children := op.children(key+prefix, alphaSpan{a, askip + shared}, alphaSpan{b, bskip + shared})
+
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		if child := children[0]; child.prefix != "" {
+			// merge new node with its only child
+			child.prefix = prefix + child.prefix
+			return child
+		}
+	}
+	return &Alpha{prefix: prefix, children: children}
+}
+
+func (op *alphaSetOp) resolveValues(key string, a, b interface{}) interface{} {
+	if op.fold {
+		fa, fb := a.(*foldedValue), b.(*foldedValue)
+		if op.resolve == nil {
+			return fa
+		}
+		return &foldedValue{key: fa.key, value: op.resolve(fa.key, fa.value, fb.value)}
+	}
+	if op.resolve == nil {
+		return a
+	}
+	return op.resolve(key, a, b)
+}
+
+// cloneFrom returns a deep copy of the node, without the first skip bytes of
+// its prefix. When copyValue is not nil, it is invoked to copy each value.
+func (tn *Alpha) cloneFrom(skip int, copyValue func(interface{}) interface{}) *Alpha {
+	node := &Alpha{prefix: tn.prefix[skip:], value: tn.value}
+	if tn.prefix == "" && copyValue != nil {
+		node.value = copyValue(tn.value)
+	}
+	if len(tn.children) > 0 {
+		node.children = make([]*Alpha, len(tn.children))
+		for i, child := range tn.children {
+			node.children[i] = child.cloneFrom(0, copyValue)
+		}
+	}
+	return node
+}
@@ -0,0 +1,152 @@
+package goradix
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// randomAlphaKeys returns count random keys made from a small alphabet, so that
+// keys often share prefixes with one another.
+func randomAlphaKeys(r *rand.Rand, count int) []string {
+	const alphabet = "abc/"
+	keys := make([]string, count)
+	for i := range keys {
+		b := make([]byte, r.Intn(8))
+		for j := range b {
+			b[j] = alphabet[r.Intn(len(alphabet))]
+		}
+		keys[i] = string(b)
+	}
+	return keys
+}
+
+// alphaContents returns the key value pairs of the Trie as a map.
+func alphaContents(tn *Alpha) map[string]interface{} {
+	m := make(map[string]interface{})
+	for _, key := range tn.Keys("", 0) {
+		m[key], _ = tn.Load(key)
+	}
+	return m
+}
+
+func TestAlphaSetOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for iteration := 0; iteration < 200; iteration++ {
+		a, b := new(Alpha), new(Alpha)
+		ma, mb := make(map[string]interface{}), make(map[string]interface{})
+		for _, key := range randomAlphaKeys(r, r.Intn(30)) {
+			a.Store(key, "a:"+key)
+			ma[key] = "a:" + key
+		}
+		for _, key := range randomAlphaKeys(r, r.Intn(30)) {
+			b.Store(key, "b:"+key)
+			mb[key] = "b:" + key
+		}
+
+		resolve := func(key string, va, vb interface{}) interface{} {
+			return va.(string) + "+" + vb.(string)
+		}
+
+		union := make(map[string]interface{})
+		intersect := make(map[string]interface{})
+		difference := make(map[string]interface{})
+		for key, va := range ma {
+			if vb, ok := mb[key]; ok {
+				union[key] = resolve(key, va, vb)
+				intersect[key] = resolve(key, va, vb)
+			} else {
+				union[key] = va
+				difference[key] = va
+			}
+		}
+		for key, vb := range mb {
+			if _, ok := ma[key]; !ok {
+				union[key] = vb
+			}
+		}
+
+		if got, want := alphaContents(a.Union(b, resolve)), union; !reflect.DeepEqual(got, want) {
+			t.Fatalf("Union\nGOT:  %v\nWANT: %v", got, want)
+		}
+		if got, want := alphaContents(a.Intersect(b, resolve)), intersect; !reflect.DeepEqual(got, want) {
+			t.Fatalf("Intersect\nGOT:  %v\nWANT: %v", got, want)
+		}
+		if got, want := alphaContents(a.Difference(b)), difference; !reflect.DeepEqual(got, want) {
+			t.Fatalf("Difference\nGOT:  %v\nWANT: %v", got, want)
+		}
+
+		// Neither operand may be modified.
+		if got, want := alphaContents(a), ma; !reflect.DeepEqual(got, want) {
+			t.Fatalf("GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := alphaContents(b), mb; !reflect.DeepEqual(got, want) {
+			t.Fatalf("GOT: %v; WANT: %v", got, want)
+		}
+	}
+}
+
+func TestAlphaUnionResultIsIndependent(t *testing.T) {
+	a, b := new(Alpha), new(Alpha)
+	a.Store("roberta", 1)
+	b.Store("roberto", 2)
+
+	union := a.Union(b, nil)
+	union.Store("robert", 3)
+	union.Delete("roberta")
+
+	keys := union.Keys("", 0)
+	sort.Strings(keys)
+	if got, want := keys, []string{"robert", "roberto"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+	if got, want := a.Keys("", 0), []string{"roberta"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+	if got, want := b.Keys("", 0), []string{"roberto"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+}
+
+func TestAlphaIntersectMergesNodes(t *testing.T) {
+	a, b := new(Alpha), new(Alpha)
+	a.Store("roberta", 1)
+	a.Store("roberto", 2)
+	b.Store("roberto", 3)
+	b.Store("sam", 4)
+
+	intersect := a.Intersect(b, nil)
+
+	// The node for "robert" only has a single child left, so it must be merged
+	// with that child.
+	if got, want := len(intersect.children), 1; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := intersect.children[0].prefix, "roberto"; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := intersect.children[0].children[0].value, 2; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestFoldingAlphaUnion(t *testing.T) {
+	a, b := NewFoldingAlpha(FoldCase), NewFoldingAlpha(FoldCase)
+	a.Store("Sam", 1)
+	b.Store("SAM", 2)
+	b.Store("Sally", 3)
+
+	union := a.Union(b, func(key string, va, vb interface{}) interface{} {
+		return va.(int) + vb.(int)
+	})
+
+	if got, want := union.Keys("", 0), []string{"Sally", "Sam"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+	value, _ := union.Load("sam")
+	if got, want := value, 3; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
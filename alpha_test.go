@@ -823,6 +823,46 @@ func TestAlphaStoreSallyInTrieWithSam(t *testing.T) {
 	}
 }
 
+func TestAlphaStoreSamInTrieWithSamuel(t *testing.T) {
+	root := &Alpha{
+		children: []*Alpha{
+			&Alpha{
+				prefix:   "samuel",
+				children: []*Alpha{&Alpha{value: 1}},
+			},
+		},
+	}
+
+	root.Store("sam", 2)
+	t.Log("\n" + string(root.Bytes()))
+
+	if got, want := len(root.children), 1; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+
+	// "sam"
+	if got, want := root.children[0].prefix, "sam"; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := len(root.children[0].children), 2; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := root.children[0].children[0].prefix, ""; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := root.children[0].children[0].value, 2; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+
+	// "samuel"
+	if got, want := root.children[0].children[1].prefix, "uel"; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := root.children[0].children[1].children[0].value, 1; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
 func TestAlphaStoreOrder(t *testing.T) {
 	root := new(Alpha)
 	root.Store("/Cabinda/Earle/Dabih", 1)
@@ -889,7 +929,6 @@ func TestAlphaKeys(t *testing.T) {
 	root.Display(os.Stderr)
 
 	t.Run("empty key", func(t *testing.T) {
-		t.Skip("hide output")
 		keys := root.Keys("", -1)
 		if got, want := len(keys), 11; got != want {
 			t.Fatalf("GOT: %v; WANT: %v", got, want)
@@ -897,7 +936,6 @@ func TestAlphaKeys(t *testing.T) {
 	})
 
 	t.Run("entire key", func(t *testing.T) {
-		t.Skip("hide output")
 		keys := root.Keys("/Baalath/Cabinda/Aaron/Aaron/Cabinda/Dabih", -1)
 		if got, want := len(keys), 1; got != want {
 			t.Fatalf("GOT: %v; WANT: %v", got, want)
@@ -908,7 +946,6 @@ func TestAlphaKeys(t *testing.T) {
 	})
 
 	t.Run("prefix of several keys", func(t *testing.T) {
-		t.Skip("not working")
 		keys := root.Keys("/Cabinda/Earle", -1)
 		t.Log(keys)
 		if got, want := len(keys), 3; got != want {
@@ -926,6 +963,35 @@ func TestAlphaKeys(t *testing.T) {
 	})
 }
 
+func TestAlphaKeysBelowStartingNode(t *testing.T) {
+	root := new(Alpha)
+	root.Store("sam", 1)
+	root.Store("samuel", 2)
+	root.Store("sally", 3)
+
+	cases := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		{"", 0, []string{"sally", "sam", "samuel"}},
+		{"s", 2, []string{"sally", "sam"}},
+		{"sam", 0, []string{"sam", "samuel"}},
+		{"samu", 0, []string{"samuel"}},
+		{"samuel", 0, []string{"samuel"}},
+		{"samuelx", 0, nil},
+		{"samx", 0, nil},
+		{"x", 0, nil},
+	}
+
+	for _, item := range cases {
+		keys := root.Keys(item.prefix, item.limit)
+		if got, want := fmt.Sprint(keys), fmt.Sprint(item.want); got != want {
+			t.Errorf("%q; GOT: %v; WANT: %v", item.prefix, got, want)
+		}
+	}
+}
+
 func TestAlphaStoreOrder2(t *testing.T) {
 	root := new(Alpha)
 	root.Store("/Cabinda/Earle/Dabih/Aaron/Aaron/Baalath/Earle/Earle/Aaron/Baalath", 0)
@@ -942,6 +1008,85 @@ func TestAlphaStoreOrder2(t *testing.T) {
 	fmt.Println(string(root.Bytes()))
 }
 
+func TestAlphaLongestPrefix(t *testing.T) {
+	root := new(Alpha)
+	root.Store("/", 1)
+	root.Store("/users", 2)
+	root.Store("/users/sam", 3)
+
+	cases := []struct {
+		key, prefix string
+		value       interface{}
+		ok          bool
+	}{
+		{"", "", nil, false},
+		{"users", "", nil, false},
+		{"/", "/", 1, true},
+		{"/groups", "/", 1, true},
+		{"/users", "/users", 2, true},
+		{"/users/", "/users", 2, true},
+		{"/users/sally", "/users", 2, true},
+		{"/users/sam", "/users/sam", 3, true},
+		{"/users/samuel", "/users/sam", 3, true},
+	}
+
+	for _, item := range cases {
+		prefix, value, ok := root.LongestPrefix(item.key)
+		if got, want := ok, item.ok; got != want {
+			t.Errorf("%q; GOT: %v; WANT: %v", item.key, got, want)
+		}
+		if got, want := prefix, item.prefix; got != want {
+			t.Errorf("%q; GOT: %v; WANT: %v", item.key, got, want)
+		}
+		if got, want := value, item.value; got != want {
+			t.Errorf("%q; GOT: %v; WANT: %v", item.key, got, want)
+		}
+	}
+}
+
+func TestAlphaPrefixes(t *testing.T) {
+	root := new(Alpha)
+	root.Store("", 0)
+	root.Store("/", 1)
+	root.Store("/users", 2)
+	root.Store("/users/sam", 3)
+	root.Store("/users/sally", 4)
+
+	cases := []struct {
+		key    string
+		values []interface{}
+	}{
+		{"", []interface{}{0}},
+		{"users", []interface{}{0}},
+		{"/groups", []interface{}{0, 1}},
+		{"/users/sa", []interface{}{0, 1, 2}},
+		{"/users/samuel", []interface{}{0, 1, 2, 3}},
+	}
+
+	for _, item := range cases {
+		var values []interface{}
+		root.prefixes(item.key, func(length int, value interface{}) bool {
+			if got, want := item.key[:length], []string{"", "/", "/users", "/users/sam"}[len(values)]; got != want {
+				t.Errorf("%q; GOT: %q; WANT: %q", item.key, got, want)
+			}
+			values = append(values, value)
+			return true
+		})
+		if got, want := fmt.Sprint(values), fmt.Sprint(item.values); got != want {
+			t.Errorf("%q; GOT: %v; WANT: %v", item.key, got, want)
+		}
+	}
+
+	var calls int
+	root.prefixes("/users/sam", func(int, interface{}) bool {
+		calls++
+		return false
+	})
+	if got, want := calls, 1; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
 // delete empty string from trie with empty string and no others
 // delete empty string from trie with empty string and others
 // delete key from trie without key
@@ -1166,3 +1311,42 @@ func TestAlphaDelete(t *testing.T) {
 		}
 	})
 }
+
+func TestAlphaDeleteLeavesValueOfParent(t *testing.T) {
+	root := new(Alpha)
+	root.Store("robert", 1)
+	root.Store("roberta", 2)
+	root.Store("sam", 3)
+
+	root.Delete("roberta")
+	t.Log("\n" + string(root.Bytes()))
+
+	value, ok := root.Load("robert")
+	if got, want := ok, true; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := value, 1; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	// Removing "robert" leaves the root with a single child, which is not
+	// merged into the root.
+	root.Delete("robert")
+	t.Log("\n" + string(root.Bytes()))
+
+	if got, want := root.prefix, ""; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := len(root.children), 1; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := root.children[0].prefix, "sam"; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+
+	root.Delete("sam")
+
+	if got, want := len(root.children), 0; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package goradix
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Validate returns an error describing the first node, in depth-first order,
+// that violates the structural invariants of the Trie, or nil when there is
+// none. The error includes the path to that node, both as the bytes of the
+// prefixes leading to it, and as the index of each child followed from the
+// root node.
+//
+// Every node's children are sorted by prefix, and no two of them start with the
+// same byte. A value node has an empty prefix, no children, and is always the
+// first child of its parent. Every other node below the root node has a
+// non-empty prefix, and either several children, or only its value node.
+//
+// Validate visits each node once, and only formats the error once it finds a
+// violation, so it is cheap enough to call after every operation of a fuzz
+// test.
+func (tn *Alpha) Validate() error {
+	if tn.prefix != "" {
+		return errors.New("invalid trie at root: root node has a prefix")
+	}
+	if tn.value != nil && tn.header() == nil {
+		return errors.New("invalid trie at root: root node holds a value")
+	}
+	v := &alphaValidator{fold: tn.folder() != nil}
+	if !v.children(tn) {
+		return v.err
+	}
+	return nil
+}
+
+type alphaValidator struct {
+	fold   bool   // values are stored as *foldedValue
+	path   []int  // index of each child followed from the root node
+	prefix []byte // bytes of the prefixes followed from the root node
+	err    error
+}
+
+// fail records the violation at the child with the specified index of the
+// current node, and returns false.
+func (v *alphaValidator) fail(node *Alpha, index int, reason string) bool {
+	indexes := make([]string, 0, len(v.path)+1)
+	for _, i := range v.path {
+		indexes = append(indexes, strconv.Itoa(i))
+	}
+	indexes = append(indexes, strconv.Itoa(index))
+	v.err = fmt.Errorf("invalid trie at %q (children %s): %s", string(v.prefix)+node.prefix, strings.Join(indexes, "/"), reason)
+	return false
+}
+
+// children validates the children of the specified node, and every node below
+// them, and returns false when it finds a violation.
+func (v *alphaValidator) children(tn *Alpha) bool {
+	for i, child := range tn.children {
+		if child == nil {
+			return v.fail(&Alpha{}, i, "nil child")
+		}
+
+		if child.prefix == "" {
+			switch {
+			case i > 0:
+				return v.fail(child, i, "value node is not the first child")
+			case len(child.children) > 0:
+				return v.fail(child, i, "value node has children")
+			case v.fold:
+				if _, ok := child.value.(*foldedValue); !ok {
+					return v.fail(child, i, "value node of folding trie does not hold a folded value")
+				}
+			}
+			continue
+		}
+
+		if i > 0 {
+			previous := tn.children[i-1].prefix
+			if previous != "" && previous[0] >= child.prefix[0] {
+				if previous[0] == child.prefix[0] {
+					return v.fail(child, i, fmt.Sprintf("shares its first byte with sibling %q", previous))
+				}
+				return v.fail(child, i, fmt.Sprintf("sorts before sibling %q", previous))
+			}
+		}
+		switch len(child.children) {
+		case 0:
+			return v.fail(child, i, "internal node has no children")
+		case 1:
+			if child.children[0].prefix != "" {
+				return v.fail(child, i, "internal node has a single child that is not its value node")
+			}
+		}
+		if child.value != nil {
+			return v.fail(child, i, "internal node holds a value")
+		}
+
+		v.path = append(v.path, i)
+		v.prefix = append(v.prefix, child.prefix...)
+		if !v.children(child) {
+			return false
+		}
+		v.prefix = v.prefix[:len(v.prefix)-len(child.prefix)]
+		v.path = v.path[:len(v.path)-1]
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package goradix
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestAlphaValidate(t *testing.T) {
+	trie := new(Alpha)
+	if err := trie.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	keys := []string{"", "romane", "romanus", "romulus", "rubens", "ruber", "rubicon", "rubicundus", "rom", "r"}
+	for i, key := range keys {
+		trie.Store(key, i)
+		if err := trie.Validate(); err != nil {
+			t.Fatalf("after storing %q: %s", key, err)
+		}
+	}
+	for _, key := range keys {
+		trie.Delete(key)
+		if err := trie.Validate(); err != nil {
+			t.Fatalf("after deleting %q: %s", key, err)
+		}
+	}
+}
+
+func TestAlphaValidateRandom(t *testing.T) {
+	const alphabet = "abc"
+	rng := rand.New(rand.NewSource(1))
+	randomKey := func() string {
+		b := make([]byte, rng.Intn(5))
+		for i := range b {
+			b[i] = alphabet[rng.Intn(len(alphabet))]
+		}
+		return string(b)
+	}
+
+	for _, trie := range []*Alpha{new(Alpha), NewFoldingAlpha(FoldCase)} {
+		for i := 0; i < 2000; i++ {
+			key := randomKey()
+			if rng.Intn(3) == 0 {
+				trie.Delete(key)
+			} else {
+				trie.Store(key, i)
+			}
+			if err := trie.Validate(); err != nil {
+				t.Fatalf("after operation %d on %q: %s\n%s", i, key, err, trie.Bytes())
+			}
+		}
+	}
+}
+
+func TestAlphaValidateViolations(t *testing.T) {
+	value := func(v interface{}) *Alpha { return &Alpha{value: v} }
+	leaf := func(prefix string) *Alpha { return &Alpha{prefix: prefix, children: []*Alpha{value(1)}} }
+
+	cases := []struct {
+		name string
+		trie *Alpha
+		want string
+	}{
+		{
+			name: "root prefix",
+			trie: &Alpha{prefix: "a"},
+			want: "root node has a prefix",
+		},
+		{
+			name: "root value",
+			trie: &Alpha{value: 1},
+			want: "root node holds a value",
+		},
+		{
+			name: "unsorted",
+			trie: &Alpha{children: []*Alpha{leaf("b"), leaf("a")}},
+			want: `invalid trie at "a" (children 1): sorts before sibling "b"`,
+		},
+		{
+			name: "shared first byte",
+			trie: &Alpha{children: []*Alpha{leaf("ab"), leaf("ac")}},
+			want: `invalid trie at "ac" (children 1): shares its first byte with sibling "ab"`,
+		},
+		{
+			name: "value node not first",
+			trie: &Alpha{children: []*Alpha{leaf("a"), value(1)}},
+			want: "value node is not the first child",
+		},
+		{
+			name: "value node with children",
+			trie: &Alpha{children: []*Alpha{{children: []*Alpha{leaf("a")}}}},
+			want: "value node has children",
+		},
+		{
+			name: "single child",
+			trie: &Alpha{children: []*Alpha{{prefix: "a", children: []*Alpha{leaf("b")}}}},
+			want: "internal node has a single child that is not its value node",
+		},
+		{
+			name: "no children",
+			trie: &Alpha{children: []*Alpha{{prefix: "a"}}},
+			want: "internal node has no children",
+		},
+		{
+			name: "nested",
+			trie: &Alpha{children: []*Alpha{
+				leaf("a"),
+				{prefix: "b", children: []*Alpha{value(1), leaf("d"), leaf("c")}},
+			}},
+			want: `invalid trie at "bc" (children 1/2): sorts before sibling "d"`,
+		},
+		{
+			name: "folding value",
+			trie: &Alpha{value: &alphaHeader{fold: FoldCase}, children: []*Alpha{leaf("a")}},
+			want: "value node of folding trie does not hold a folded value",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.trie.Validate()
+			if err == nil {
+				t.Fatalf("GOT: %v; WANT: %q", err, c.want)
+			}
+			if !strings.Contains(err.Error(), c.want) {
+				t.Errorf("GOT: %q; WANT: %q", err, c.want)
+			}
+		})
+	}
+}
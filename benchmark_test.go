@@ -18,6 +18,18 @@ var checkValues, insertValues []uint64
 var m map[uint64]struct{}
 var bravo *Bravo
 var charlie *Charlie
+var mv map[uint64]interface{}
+var bravoMap *BravoMap
+var charlieMap *CharlieMap
+var delta *Delta
+var bitmapBravo *Bravo
+var bitmapCharlie *Charlie
+var echo *Echo
+var sparseCharlie *Charlie
+
+// heap bytes allocated while building each structure
+var mapHeap, bravoHeap, deltaHeap, bitmapBravoHeap, charlie8Heap, echoHeap, sparseCharlieHeap uint64
+var mapValuesHeap, bravoMapHeap, charlieMap8Heap, bitmapCharlie8Heap uint64
 
 func init() {
 	checkValues = make([]uint64, keycount)
@@ -38,33 +50,81 @@ func getHeapAlloc() uint64 {
 	return ms.HeapAlloc
 }
 
+// reportHeapPerKey reports the heap bytes used by a structure holding keycount
+// keys, divided by keycount.
+func reportHeapPerKey(b *testing.B, heap uint64) {
+	b.ReportMetric(float64(heap)/keycount, "heap-B/key")
+}
+
 func BenchmarkMap(b *testing.B) {
 	if m == nil {
 		log.Printf("building map")
+		before := getHeapAlloc()
 		m = make(map[uint64]struct{})
 		for i := 0; i < keycount; i++ {
 			m[insertValues[i]] = struct{}{}
 		}
+		mapHeap = getHeapAlloc() - before
 		b.ResetTimer()
 	}
 	for i := 0; i < b.N; i++ {
 		_ = m[checkValues[i%keycount]]
 	}
+	reportHeapPerKey(b, mapHeap)
 }
 
 func BenchmarkBravo(b *testing.B) {
 	if bravo == nil {
 		log.Printf("building bravo")
+		before := getHeapAlloc()
 		bravo = NewBravo()
 		for i := 0; i < keycount; i++ {
 			bravo.Store(insertValues[i])
 		}
-		log.Printf("keycount: %d; bravo.nodes: %d; footprint: %d", keycount, bravo.Count, bravo.Count*16)
+		bravoHeap = getHeapAlloc() - before
+		log.Printf("keycount: %d; bravo.nodes: %d", keycount, bravo.Nodes())
 		b.ResetTimer()
 	}
 	for i := 0; i < b.N; i++ {
 		_ = bravo.Load(checkValues[i%keycount])
 	}
+	reportHeapPerKey(b, bravoHeap)
+}
+
+func BenchmarkDelta(b *testing.B) {
+	if delta == nil {
+		log.Printf("building delta")
+		before := getHeapAlloc()
+		delta = NewDelta()
+		for i := 0; i < keycount; i++ {
+			delta.Store(insertValues[i])
+		}
+		deltaHeap = getHeapAlloc() - before
+		log.Printf("keycount: %d; delta.nodes: %d", keycount, delta.Nodes())
+		b.ResetTimer()
+	}
+	for i := 0; i < b.N; i++ {
+		_ = delta.Load(checkValues[i%keycount])
+	}
+	reportHeapPerKey(b, deltaHeap)
+}
+
+func BenchmarkBitmapBravo(b *testing.B) {
+	if bitmapBravo == nil {
+		log.Printf("building bitmap bravo")
+		before := getHeapAlloc()
+		bitmapBravo = NewBitmapBravo()
+		for i := 0; i < keycount; i++ {
+			bitmapBravo.Store(insertValues[i])
+		}
+		bitmapBravoHeap = getHeapAlloc() - before
+		log.Printf("keycount: %d; bitmapBravo.nodes: %d", keycount, bitmapBravo.Nodes())
+		b.ResetTimer()
+	}
+	for i := 0; i < b.N; i++ {
+		_ = bitmapBravo.Load(checkValues[i%keycount])
+	}
+	reportHeapPerKey(b, bitmapBravoHeap)
 }
 
 func BenchmarkCharlie4(b *testing.B) {
@@ -76,7 +136,7 @@ func BenchmarkCharlie4(b *testing.B) {
 		for i := 0; i < keycount; i++ {
 			charlie.Store(insertValues[i])
 		}
-		log.Printf("keycount: %d; charlie.nodes: %d; footprint: %d", keycount, charlie.Count, charlie.Sizeof())
+		log.Printf("keycount: %d; charlie.nodes: %d; footprint: %d", keycount, charlie.Nodes(), charlie.Sizeof())
 		b.ResetTimer()
 	}
 	for i := 0; i < b.N; i++ {
@@ -89,28 +149,96 @@ func BenchmarkCharlie8(b *testing.B) {
 	bits := uint8(8)
 	if charlie == nil {
 		log.Printf("building charlie")
+		before := getHeapAlloc()
 		charlie = NewCharlie(bits)
 		for i := 0; i < keycount; i++ {
 			charlie.Store(insertValues[i])
 		}
-		log.Printf("keycount: %d; charlie.nodes: %d; footprint: %d", keycount, charlie.Count, charlie.Sizeof())
+		charlie8Heap = getHeapAlloc() - before
+		log.Printf("keycount: %d; charlie.nodes: %d; footprint: %d", keycount, charlie.Nodes(), charlie.Sizeof())
 		b.ResetTimer()
 	}
 	for i := 0; i < b.N; i++ {
 		_ = charlie.Load(checkValues[i%keycount])
 	}
+	reportHeapPerKey(b, charlie8Heap)
 	charlie = nil
 }
 
+func BenchmarkSparseCharlie4(b *testing.B) {
+	benchmarkSparseCharlie(b, 4)
+}
+
+func BenchmarkSparseCharlie8(b *testing.B) {
+	benchmarkSparseCharlie(b, 8)
+}
+
+func benchmarkSparseCharlie(b *testing.B, bits uint8) {
+	if sparseCharlie == nil {
+		log.Printf("building sparse charlie")
+		before := getHeapAlloc()
+		sparseCharlie = NewSparseCharlie(bits)
+		for i := 0; i < keycount; i++ {
+			sparseCharlie.Store(insertValues[i])
+		}
+		sparseCharlieHeap = getHeapAlloc() - before
+		log.Printf("keycount: %d; sparseCharlie.nodes: %d", keycount, sparseCharlie.Nodes())
+		b.ResetTimer()
+	}
+	for i := 0; i < b.N; i++ {
+		_ = sparseCharlie.Load(checkValues[i%keycount])
+	}
+	reportHeapPerKey(b, sparseCharlieHeap)
+	sparseCharlie = nil
+}
+
+func BenchmarkEcho(b *testing.B) {
+	if echo == nil {
+		log.Printf("building echo")
+		before := getHeapAlloc()
+		echo = NewEcho()
+		for i := 0; i < keycount; i++ {
+			echo.Store(insertValues[i])
+		}
+		echoHeap = getHeapAlloc() - before
+		log.Printf("keycount: %d; echo.nodes: %d", keycount, echo.Nodes())
+		b.ResetTimer()
+	}
+	for i := 0; i < b.N; i++ {
+		_ = echo.Load(checkValues[i%keycount])
+	}
+	reportHeapPerKey(b, echoHeap)
+}
+
 func (charlie *Charlie) Sizeof() uint64 {
 	var f *cnode
 	sizePointer := uint64(unsafe.Sizeof(f))
 	sizePointers := charlie.childCount * sizePointer
 	sizeNode := uint64(unsafe.Sizeof(charlie.head)) + sizePointers
-	sizeNodes := charlie.Count * sizeNode
+	sizeNodes := charlie.Nodes() * sizeNode
 	return uint64(unsafe.Sizeof(charlie)) + sizeNodes
 }
 
+func BenchmarkBitmapCharlie8(b *testing.B) {
+	bits := uint8(8)
+	if bitmapCharlie == nil {
+		log.Printf("building bitmap charlie")
+		before := getHeapAlloc()
+		bitmapCharlie = NewBitmapCharlie(bits)
+		for i := 0; i < keycount; i++ {
+			bitmapCharlie.Store(insertValues[i])
+		}
+		bitmapCharlie8Heap = getHeapAlloc() - before
+		log.Printf("keycount: %d; bitmapCharlie.nodes: %d", keycount, bitmapCharlie.Nodes())
+		b.ResetTimer()
+	}
+	for i := 0; i < b.N; i++ {
+		_ = bitmapCharlie.Load(checkValues[i%keycount])
+	}
+	reportHeapPerKey(b, bitmapCharlie8Heap)
+	bitmapCharlie = nil
+}
+
 func BenchmarkCharlie16(b *testing.B) {
 	bits := uint8(16)
 	if charlie == nil {
@@ -119,7 +247,7 @@ func BenchmarkCharlie16(b *testing.B) {
 		for i := 0; i < keycount; i++ {
 			charlie.Store(insertValues[i])
 		}
-		log.Printf("keycount: %d; charlie.nodes: %d; footprint: %d", keycount, charlie.Count, charlie.Sizeof())
+		log.Printf("keycount: %d; charlie.nodes: %d; footprint: %d", keycount, charlie.Nodes(), charlie.Sizeof())
 		b.ResetTimer()
 	}
 	for i := 0; i < b.N; i++ {
@@ -127,3 +255,58 @@ func BenchmarkCharlie16(b *testing.B) {
 	}
 	charlie = nil
 }
+
+func BenchmarkMapValues(b *testing.B) {
+	if mv == nil {
+		log.Printf("building map of values")
+		before := getHeapAlloc()
+		mv = make(map[uint64]interface{})
+		for i := 0; i < keycount; i++ {
+			mv[insertValues[i]] = i
+		}
+		mapValuesHeap = getHeapAlloc() - before
+		b.ResetTimer()
+	}
+	for i := 0; i < b.N; i++ {
+		_ = mv[checkValues[i%keycount]]
+	}
+	reportHeapPerKey(b, mapValuesHeap)
+}
+
+func BenchmarkBravoMap(b *testing.B) {
+	if bravoMap == nil {
+		log.Printf("building bravo map")
+		before := getHeapAlloc()
+		bravoMap = NewBravoMap()
+		for i := 0; i < keycount; i++ {
+			bravoMap.Store(insertValues[i], i)
+		}
+		bravoMapHeap = getHeapAlloc() - before
+		log.Printf("keycount: %d; bravoMap.nodes: %d", keycount, bravoMap.Nodes())
+		b.ResetTimer()
+	}
+	for i := 0; i < b.N; i++ {
+		_, _ = bravoMap.Load(checkValues[i%keycount])
+	}
+	reportHeapPerKey(b, bravoMapHeap)
+}
+
+func BenchmarkCharlieMap8(b *testing.B) {
+	bits := uint8(8)
+	if charlieMap == nil {
+		log.Printf("building charlie map")
+		before := getHeapAlloc()
+		charlieMap = NewCharlieMap(bits)
+		for i := 0; i < keycount; i++ {
+			charlieMap.Store(insertValues[i], i)
+		}
+		charlieMap8Heap = getHeapAlloc() - before
+		log.Printf("keycount: %d; charlieMap.nodes: %d", keycount, charlieMap.Nodes())
+		b.ResetTimer()
+	}
+	for i := 0; i < b.N; i++ {
+		_, _ = charlieMap.Load(checkValues[i%keycount])
+	}
+	reportHeapPerKey(b, charlieMap8Heap)
+	charlieMap = nil
+}
@@ -0,0 +1,144 @@
+package goradix
+
+import "math/bits"
+
+// bitmapBits is the number of final bits of each key that a Bravo or Charlie
+// with bitmap leaves keeps as one bit of a 64-bit bitmap, rather than as nodes.
+const bitmapBits = 6
+
+// NewBitmapBravo returns a new DST whose keys end in bitmaps. Rather than a node
+// for each of the final 6 bits of a key, the node at depth 58 holds a bitmap of
+// those bits for every key that shares its path, in place of as many as 126
+// nodes below it.
+func NewBitmapBravo() *Bravo {
+	return &Bravo{root: new(bnode), nodes: 1, leafBits: bitmapBits}
+}
+
+// NewBitmapCharlie returns a new tree like NewCharlie, but whose node above
+// the final 6 bits of a key holds a bitmap of those bits for every key that
+// shares its path, rather than having children for them.
+func NewBitmapCharlie(bits uint8) *Charlie {
+	tree := NewCharlie(bits)
+	tree.leafBits = bitmapBits
+	// The digits cover the other 58 bits of a key, so when the bits do not
+	// divide 58, the first digit of each key is shorter than the others.
+	tree.bitInit = bitmapBits + (63-bitmapBits)/tree.bitStep*tree.bitStep
+	return tree
+}
+
+// bitmapRange returns the bits of a bitmap whose keys are not less than lo, and
+// not greater than hi, where bit i of the bitmap stands for the key key|i.
+func bitmapRange(key, lo, hi uint64) uint64 {
+	if hi < key || lo > key && lo-key > 63 {
+		return 0
+	}
+	r := ^uint64(0)
+	if lo > key {
+		r <<= lo - key
+	}
+	if hi-key < 63 {
+		r &= 1<<(hi-key+1) - 1
+	}
+	return r
+}
+
+// bitmapExtreme returns the key of the lowest bit set in the bitmap, or of the
+// highest when max is true, where bit i stands for the key key|i. It returns
+// false when no bit is set.
+func bitmapExtreme(bitmap, key uint64, max bool) (uint64, bool) {
+	if bitmap == 0 {
+		return 0, false
+	}
+	if max {
+		return key | uint64(63-bits.LeadingZeros64(bitmap)), true
+	}
+	return key | uint64(bits.TrailingZeros64(bitmap)), true
+}
+
+// walkBitmap invokes fn with the key of each bit set in the bitmap, where bit i
+// stands for the key key|i, like walk does for the keys below a node.
+func walkBitmap(bitmap, key, start uint64, bounded, reverse bool, fn func(uint64) bool) bool {
+	if bounded {
+		if reverse {
+			bitmap &= bitmapRange(key, 0, start)
+		} else {
+			bitmap &= bitmapRange(key, start, ^uint64(0))
+		}
+	}
+	for bitmap != 0 {
+		i := bits.TrailingZeros64(bitmap)
+		if reverse {
+			i = 63 - bits.LeadingZeros64(bitmap)
+		}
+		bitmap &^= 1 << uint(i)
+		if !fn(key | uint64(i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// tail returns the mask of the final bits of each key that are kept in a
+// bitmap, or 0 when the DST has no bitmap leaves.
+func (dst *Bravo) tail() uint64 {
+	return 1<<dst.leafBits - 1
+}
+
+// leaves returns the bitmap of the keys that end at the node, where tail is the
+// mask of the bits of the key that the bitmap stands for. Without bitmap
+// leaves, tail is 0, and the node is the leaf of a single key.
+func (n *bnode) leaves(tail uint64) uint64 {
+	if tail == 0 {
+		return 1
+	}
+	return n.word
+}
+
+// isEmpty returns true when the node, where mask is the bit of the key below
+// it, has neither children nor any key in its bitmap.
+func (n *bnode) isEmpty(mask, tail uint64) bool {
+	if mask <= tail {
+		return n.leaves(tail) == 0
+	}
+	return n.left == nil && n.right == nil
+}
+
+// endLevel returns the level of the nodes at which keys end, which are the
+// leaves of the tree, or the nodes that hold bitmaps.
+func (tree *Charlie) endLevel() int {
+	return int(tree.bitInit-tree.leafBits)/int(tree.bitStep) + 1
+}
+
+// shift returns the shift of the digit of a key that chooses among the
+// children of a node at the specified level.
+func (tree *Charlie) shift(level int) uint {
+	return uint(int(tree.bitInit) - int(tree.bitStep)*level)
+}
+
+// leafBit returns the bit of a bitmap that stands for the final bits of key.
+func (tree *Charlie) leafBit(key uint64) uint64 {
+	return 1 << (key & (1<<tree.leafBits - 1))
+}
+
+// newNode returns a new node for the child of a node that is chosen by the digit
+// of the key at the specified shift.
+func (tree *Charlie) newNode(bits uint8) *cnode {
+	switch {
+	case tree.leafBits != 0 && bits == tree.leafBits:
+		return new(cnode) // the node holds a bitmap rather than children
+	case tree.sparse && bits == 0:
+		return new(cnode) // a leaf has no children
+	case tree.sparse:
+		return &cnode{digits: make([]uint64, tree.childWords())}
+	}
+	return &cnode{children: make([]*cnode, tree.childCount)}
+}
+
+// leafCount returns the number of keys that end at a node at the end level of
+// the tree: one for a leaf, and the number of keys in the bitmap otherwise.
+func (tree *Charlie) leafCount(n *cnode) uint64 {
+	if tree.leafBits == 0 {
+		return 1
+	}
+	return uint64(bits.OnesCount64(n.word))
+}
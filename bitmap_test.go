@@ -0,0 +1,167 @@
+package goradix
+
+import (
+	"math/rand"
+	"testing"
+	"unsafe"
+)
+
+func TestBitmapRange(t *testing.T) {
+	cases := []struct {
+		key, lo, hi, want uint64
+	}{
+		{0x40, 0, ^uint64(0), ^uint64(0)},
+		{0x40, 0x42, ^uint64(0), ^uint64(3)},
+		{0x40, 0, 0x42, 0x7},
+		{0x40, 0x41, 0x43, 0xe},
+		{0x40, 0x80, ^uint64(0), 0},
+		{0x40, 0, 0x3f, 0},
+		{0x40, 0x7f, 0x7f, 1 << 63},
+	}
+	for _, c := range cases {
+		if got := bitmapRange(c.key, c.lo, c.hi); got != c.want {
+			t.Errorf("bitmapRange(%#x, %#x, %#x): GOT: %#x; WANT: %#x", c.key, c.lo, c.hi, got, c.want)
+		}
+	}
+}
+
+// bitmapTrie is implemented by both Bravo and Charlie.
+type bitmapTrie interface {
+	Store(uint64)
+	Delete(uint64)
+	Load(uint64) bool
+	Len() uint64
+	Nodes() uint64
+	Stats() Stats
+}
+
+func TestBitmapLeaves(t *testing.T) {
+	tries := map[string][2]bitmapTrie{
+		"bravo":     {NewBravo(), NewBitmapBravo()},
+		"charlie-1": {NewCharlie(1), NewBitmapCharlie(1)},
+		"charlie-2": {NewCharlie(2), NewBitmapCharlie(2)},
+		"charlie-4": {NewCharlie(4), NewBitmapCharlie(4)},
+		"charlie-8": {NewCharlie(8), NewBitmapCharlie(8)},
+	}
+	for name, pair := range tries {
+		plain, bitmap := pair[0], pair[1]
+		rng := rand.New(rand.NewSource(1))
+
+		// Runs of keys that share all but their final bits, among others
+		// that share little.
+		var keys []uint64
+		for i := 0; i < 20; i++ {
+			base := rng.Uint64()
+			for j := uint64(0); j < 50; j++ {
+				keys = append(keys, base+j)
+			}
+			keys = append(keys, rng.Uint64())
+		}
+		for _, key := range keys {
+			plain.Store(key)
+			bitmap.Store(key)
+		}
+		for i, key := range keys {
+			if i%3 == 0 {
+				plain.Delete(key)
+				bitmap.Delete(key)
+			}
+		}
+
+		for i, key := range keys {
+			for _, k := range []uint64{key, key ^ 1, key ^ 1<<7} {
+				if got, want := bitmap.Load(k), plain.Load(k); got != want {
+					t.Errorf("%s: %d: Load(%#x): GOT: %v; WANT: %v", name, i, k, got, want)
+				}
+			}
+		}
+		if got, want := bitmap.Len(), plain.Len(); got != want {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, got, want)
+		}
+		if got, want := bitmap.Stats().Keys, plain.Len(); got != want {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, got, want)
+		}
+		if got, want := bitmap.Nodes(), plain.Nodes(); got >= want {
+			t.Errorf("%s: GOT: %v; WANT: fewer than %v", name, got, want)
+		}
+		if got, want := bitmap.Stats().Nodes, bitmap.Nodes(); got != want {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, got, want)
+		}
+	}
+}
+
+func TestBitmapBravoCompact(t *testing.T) {
+	for _, prune := range []bool{false, true} {
+		dst := NewBitmapBravo()
+		dst.SetPruneOnDelete(prune)
+
+		dst.Store(0x40)
+		dst.Store(0x41)
+		dst.Store(0x80)
+		dst.Delete(0x40)
+		if got, want := dst.Nodes(), uint64(61); got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+
+		// The node that holds the bitmap of 0x80 is only removed once its
+		// bitmap is empty.
+		dst.Delete(0x80)
+		freed := dst.Compact()
+		if got, want := dst.Nodes(), uint64(59); got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+		if got, want := freed, uint64(0); prune && got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+		if got, want := dst.Load(0x41), true; got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+
+		dst.Delete(0x41)
+		dst.Compact()
+		if got, want := dst.Nodes(), uint64(1); got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+	}
+}
+
+func TestBitmapCharlieNodeSizes(t *testing.T) {
+	// Only the node at the end level keeps a bitmap, in place of children.
+	var n cnode
+	tree := NewBitmapCharlie(4)
+	tree.Store(0x1234)
+	tree.Store(0x1235)
+	inner := uint64(unsafe.Sizeof(n)) + 16*uint64(unsafe.Sizeof(&n))
+	if got, want := tree.Stats().HeapBytes, uint64(unsafe.Sizeof(*tree))+15*inner+uint64(unsafe.Sizeof(n)); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	node, _ := tree.find(0x1234)
+	if got, want := node.word, uint64(0x30)<<48; got != want {
+		t.Errorf("GOT: %#x; WANT: %#x", got, want)
+	}
+}
+
+func TestBitmapCharlieLeafWords(t *testing.T) {
+	// Whatever the width of its digits, each node at the end level keeps the
+	// final 6 bits of its keys in its single word.
+	for _, bits := range []uint8{1, 2, 4, 8, 16} {
+		tree := NewBitmapCharlie(bits)
+		tree.Store(0x1234)
+		tree.Store(0x123f)
+		node, _ := tree.find(0x1234)
+		if got, want := node.word, uint64(1)<<52|1<<63; got != want {
+			t.Errorf("bits: %d; GOT: %#x; WANT: %#x", bits, got, want)
+		}
+		if got, want := tree.Nodes(), uint64(tree.endLevel()+1); got != want {
+			t.Errorf("bits: %d; GOT: %v; WANT: %v", bits, got, want)
+		}
+
+		tree.Delete(0x1234)
+		if got, want := tree.Load(0x1234), false; got != want {
+			t.Errorf("bits: %d; GOT: %v; WANT: %v", bits, got, want)
+		}
+		if got, want := tree.Load(0x123f), true; got != want {
+			t.Errorf("bits: %d; GOT: %v; WANT: %v", bits, got, want)
+		}
+	}
+}
@@ -1,30 +1,62 @@
 package goradix
 
+import (
+	"math/bits"
+	"time"
+)
+
 // NOTE: This is not really a DST, but more of an existence trie.
 
 type bnode struct {
 	left, right *bnode
+	word        uint64 // keys below the node when counting, or the bitmap of the keys ending at it
 }
 
 type Bravo struct {
-	root  *bnode
+	root *bnode
+
+	// Count is the number of nodes below the root node.
+	//
+	// Deprecated: Use Len for the number of keys, and Nodes for the number
+	// of nodes.
 	Count uint64
+
+	keys, nodes uint64
+	observer    Observer
+	prune       bool  // Delete removes the branch that led only to the key
+	counting    bool  // each node has the number of keys below it
+	leafBits    uint8 // final bits of each key kept in a bitmap, or 0
 }
 
 func NewBravo() *Bravo {
-	return &Bravo{root: new(bnode)}
+	return &Bravo{root: new(bnode), nodes: 1}
+}
+
+// Len returns the number of keys in the DST.
+func (dst *Bravo) Len() uint64 {
+	return dst.keys
+}
+
+// Nodes returns the number of nodes allocated by the DST, including its root
+// node and any branches left behind by Delete that have not yet been removed
+// by Compact.
+func (dst *Bravo) Nodes() uint64 {
+	return dst.nodes
 }
 
 const initialMask = uint64(1 << 63)
 
 // search returns the node prior to a nil pointer, followed by the mask so
-// upstream can determine whether node was located.
+// upstream can determine whether node was located. With bitmap leaves, when the
+// path of the key is complete, it returns the node that holds the bitmap of
+// the key instead, followed by 0 only when the key is in the bitmap.
 func (dst *Bravo) search(key uint64) (*bnode, uint64) {
 	var node *bnode
 	next := dst.root
 	mask := initialMask
+	tail := dst.tail()
 
-	for ; mask != 0; mask >>= 1 {
+	for ; mask > tail; mask >>= 1 {
 		node = next
 		if key&mask != 0 {
 			next = node.right
@@ -36,43 +68,100 @@ func (dst *Bravo) search(key uint64) (*bnode, uint64) {
 		}
 	}
 
+	if tail != 0 {
+		if next.word&(1<<(key&tail)) == 0 {
+			return next, mask
+		}
+		return next, 0
+	}
 	return node, mask
 }
 
-// TODO: When DST has heavy additions and removals, cleaning tree for every
-// Delete results in needless memory churn. Instead, provide a Compact method to
-// clean up dead branches independent of Delete method.
+// visited returns the number of nodes visited by search, when it returned mask.
+func (dst *Bravo) visited(mask uint64) int {
+	if mask == 0 && dst.leafBits != 0 {
+		mask = 1 << (dst.leafBits - 1) // the key was found in a bitmap
+	}
+	return bits.LeadingZeros64(mask) + 1
+}
 
 // Delete removes the specified 64-bit key from the DST.
 func (dst *Bravo) Delete(key uint64) {
+	var start time.Time
+	if dst.observer != nil {
+		start = time.Now()
+	}
+
 	node, mask := dst.search(key)
-	if mask != 0 {
-		return // key not present
+	if mask == 0 && dst.counting {
+		dst.adjustCounts(key, -1)
+	}
+	switch {
+	case mask != 0:
+		// key not present
+	case dst.leafBits != 0:
+		node.word &^= 1 << (key & dst.tail())
+		dst.keys--
+		if dst.prune && node.word == 0 {
+			dst.pruneBranch(key)
+		}
+	case dst.prune:
+		dst.keys--
+		dst.pruneBranch(key)
+	default:
+		// Check the final bit to determine whether to remove right or left
+		// branch from node.
+		if key&1 != 0 {
+			node.right = nil // remove right branch
+		} else {
+			node.left = nil // remove left branch
+		}
+		dst.keys--
+		dst.nodes--
+		dst.Count--
 	}
-	// Check the final bit to determine whether to remove right or left branch
-	// from node.
-	if key&1 != 0 {
-		node.right = nil // remove right branch
-	} else {
-		node.left = nil // remove left branch
+
+	if dst.observer != nil {
+		observe(dst.observer, start, &Observation{Op: OpDelete, Hit: mask == 0, Nodes: dst.visited(mask)})
 	}
-	dst.Count--
 }
 
 // Load returns whether or not the specified 64-bit key is present in the DST.
 func (dst *Bravo) Load(key uint64) bool {
+	if dst.observer != nil {
+		return dst.observedLoad(key)
+	}
+	_, mask := dst.search(key)
+	return mask == 0
+}
+
+// observedLoad is Load for a DST with an Observer.
+func (dst *Bravo) observedLoad(key uint64) bool {
+	start := time.Now()
 	_, mask := dst.search(key)
+	observe(dst.observer, start, &Observation{Op: OpLoad, Hit: mask == 0, Nodes: dst.visited(mask)})
 	return mask == 0
 }
 
 // Store stores the existence of the specified 64-bit key.
 func (dst *Bravo) Store(key uint64) {
+	var start time.Time
+	if dst.observer != nil {
+		start = time.Now()
+	}
+
 	// walk existing tree branches as much as possible
 	node, mask := dst.search(key)
+	hit, visited := mask == 0, dst.visited(mask)
+	if !hit {
+		dst.keys++
+	}
 
 	// create whatever branches needed
-	for ; mask != 0; mask >>= 1 {
+	tail := dst.tail()
+	for ; mask > tail; mask >>= 1 {
 		newNode := new(bnode)
+		dst.nodes++
 		dst.Count++
 		if key&mask != 0 {
 			node.right = newNode
@@ -81,4 +170,14 @@ func (dst *Bravo) Store(key uint64) {
 		}
 		node = newNode
 	}
+	if tail != 0 {
+		node.word |= 1 << (key & tail)
+	}
+	if !hit && dst.counting {
+		dst.adjustCounts(key, 1)
+	}
+
+	if dst.observer != nil {
+		observe(dst.observer, start, &Observation{Op: OpStore, Hit: hit, Nodes: visited})
+	}
 }
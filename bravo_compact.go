@@ -0,0 +1,91 @@
+package goradix
+
+import "math/bits"
+
+// SetPruneOnDelete controls whether Delete removes the entire branch that led
+// only to the deleted key, rather than only the final node of the key. Pruning
+// on every Delete keeps the DST as small as possible, at the cost of walking
+// the key a second time. When it is off, the branches left behind may be
+// removed later by Compact.
+func (dst *Bravo) SetPruneOnDelete(prune bool) {
+	dst.prune = prune
+}
+
+// pruneBranch removes the nodes of the specified key, which must be present in
+// the DST, that lead to no other key. With bitmap leaves, the path of the key
+// must be present instead, ending at a node with an empty bitmap.
+func (dst *Bravo) pruneBranch(key uint64) {
+	// The branch is cut below the deepest node that also leads somewhere
+	// other than to this key, or below the root node when there is none.
+	fork, forkMask := dst.root, initialMask
+	node := dst.root
+	tail := dst.tail()
+	for mask := initialMask; mask > tail; mask >>= 1 {
+		if node.left != nil && node.right != nil {
+			fork, forkMask = node, mask
+		}
+		if key&mask != 0 {
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+
+	if key&forkMask != 0 {
+		fork.right = nil
+	} else {
+		fork.left = nil
+	}
+	freed := uint64(64 - int(dst.leafBits) - bits.LeadingZeros64(forkMask))
+	dst.nodes -= freed
+	dst.Count -= freed
+}
+
+// Compact removes every branch of the DST that no longer leads to a key, such
+// as those left behind by Delete, and returns the number of nodes it removed.
+func (dst *Bravo) Compact() (freed uint64) {
+	if dst.root == nil {
+		return 0
+	}
+	freed, _ = dst.root.compact(initialMask, dst.tail())
+	dst.nodes -= freed
+	dst.Count -= freed
+	return freed
+}
+
+// compact removes the branches below the node that do not lead to a key, where
+// mask is the bit of the key below the node. It returns the number of nodes
+// removed, and whether the node itself leads to a key. When it does not, the
+// node is included in the number removed, and its parent must remove it.
+func (n *bnode) compact(mask, tail uint64) (uint64, bool) {
+	if mask <= tail {
+		if n.leaves(tail) == 0 {
+			return 1, false // a bitmap without keys
+		}
+		return 0, true
+	}
+	var freed uint64
+	var live bool
+	if n.left != nil {
+		f, ok := n.left.compact(mask>>1, tail)
+		freed += f
+		if ok {
+			live = true
+		} else {
+			n.left = nil
+		}
+	}
+	if n.right != nil {
+		f, ok := n.right.compact(mask>>1, tail)
+		freed += f
+		if ok {
+			live = true
+		} else {
+			n.right = nil
+		}
+	}
+	if !live && mask != initialMask {
+		freed++ // the root node is never removed
+	}
+	return freed, live
+}
@@ -0,0 +1,159 @@
+package goradix
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// checkBravoNodes fails the test when Count does not match the number of nodes
+// below the root node of the DST.
+func checkBravoNodes(t *testing.T, dst *Bravo) {
+	t.Helper()
+	if got, want := dst.Count, dst.Stats().Nodes-1; got != want {
+		t.Errorf("Count: GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestBravoCompact(t *testing.T) {
+	dst := NewBravo()
+	dst.Store(0)
+	dst.Store(1)
+	dst.Store(1 << 63)
+	dst.Delete(1 << 63)
+	dst.Delete(1)
+
+	if got, want := dst.Compact(), uint64(63); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	checkBravoNodes(t, dst)
+	if got, want := dst.Count, uint64(64); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dst.Load(0), true; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	// A second Compact has nothing left to remove.
+	if got, want := dst.Compact(), uint64(0); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	dst.Delete(0)
+	if got, want := dst.Compact(), uint64(63); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dst.Count, uint64(0); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	checkBravoNodes(t, dst)
+}
+
+func TestBravoPruneOnDelete(t *testing.T) {
+	dst := NewBravo()
+	dst.SetPruneOnDelete(true)
+	dst.Store(0)
+	dst.Store(1)
+	dst.Store(1 << 63)
+
+	dst.Delete(1 << 63)
+	if got, want := dst.Count, uint64(65); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	dst.Delete(1)
+	if got, want := dst.Count, uint64(64); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	dst.Delete(0)
+	if got, want := dst.Count, uint64(0); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	checkBravoNodes(t, dst)
+}
+
+func TestBravoCompactChurn(t *testing.T) {
+	for _, prune := range []bool{false, true} {
+		rng := rand.New(rand.NewSource(1))
+		dst := NewBravo()
+		dst.SetPruneOnDelete(prune)
+		live := make(map[uint64]struct{})
+
+		for i := 0; i < 5000; i++ {
+			key := uint64(rng.Intn(512)) << uint(rng.Intn(56))
+			if rng.Intn(2) == 0 {
+				dst.Store(key)
+				live[key] = struct{}{}
+			} else {
+				dst.Delete(key)
+				delete(live, key)
+			}
+		}
+		checkBravoNodes(t, dst)
+
+		freed := dst.Compact()
+		if prune && freed != 0 {
+			t.Errorf("GOT: %v; WANT: %v", freed, 0)
+		}
+		checkBravoNodes(t, dst)
+
+		// Every node left leads to a key, so there is one node at the end of
+		// each key, and no other node without children.
+		s := dst.Stats()
+		if got, want := s.Keys, uint64(len(live)); got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+		if len(live) > 0 && s.Fanout[0] != s.Keys {
+			t.Errorf("GOT: %v; WANT: %v", s.Fanout[0], s.Keys)
+		}
+		for key := range live {
+			if !dst.Load(key) {
+				t.Fatalf("GOT: %v; WANT: %v", false, true)
+			}
+		}
+	}
+}
+
+func TestBravoLenAndNodes(t *testing.T) {
+	for _, prune := range []bool{false, true} {
+		rng := rand.New(rand.NewSource(2))
+		dst := NewBravo()
+		dst.SetPruneOnDelete(prune)
+		live := make(map[uint64]struct{})
+
+		for i := 0; i < 5000; i++ {
+			key := uint64(rng.Intn(256)) << uint(rng.Intn(56))
+			if rng.Intn(3) == 0 {
+				dst.Delete(key)
+				delete(live, key)
+			} else {
+				dst.Store(key)
+				live[key] = struct{}{}
+			}
+			if i%500 == 0 {
+				dst.Compact()
+			}
+		}
+
+		if got, want := dst.Len(), uint64(len(live)); got != want {
+			t.Errorf("Len: GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := dst.Nodes(), dst.Stats().Nodes; got != want {
+			t.Errorf("Nodes: GOT: %v; WANT: %v", got, want)
+		}
+
+		dst.Compact()
+		if got, want := dst.Nodes(), dst.Stats().Nodes; got != want {
+			t.Errorf("Nodes: GOT: %v; WANT: %v", got, want)
+		}
+
+		for key := range live {
+			dst.Delete(key)
+		}
+		dst.Compact()
+		if got, want := dst.Len(), uint64(0); got != want {
+			t.Errorf("Len: GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := dst.Nodes(), uint64(1); got != want {
+			t.Errorf("Nodes: GOT: %v; WANT: %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package goradix
+
+import "math/bits"
+
+// bmnode is a node of a BravoMap.
+type bmnode struct {
+	left, right *bmnode
+	leaf        *interface{} // value of the key ending at the node, or nil above the leaves
+}
+
+// BravoMap is a Bravo that associates a value with each of its 64-bit keys.
+type BravoMap struct {
+	root        *bmnode
+	keys, nodes uint64
+	prune       bool // Delete removes the branch that led only to the key
+}
+
+// NewBravoMap returns a new, empty BravoMap.
+func NewBravoMap() *BravoMap {
+	return &BravoMap{root: new(bmnode), nodes: 1}
+}
+
+// search returns the node prior to a nil pointer, followed by the mask so
+// upstream can determine whether node was located. When the key is present,
+// mask is 0 and the node is its leaf.
+func (bm *BravoMap) search(key uint64) (*bmnode, uint64) {
+	node := bm.root
+	mask := initialMask
+
+	for ; mask != 0; mask >>= 1 {
+		next := node.left
+		if key&mask != 0 {
+			next = node.right
+		}
+		if next == nil {
+			return node, mask
+		}
+		node = next
+	}
+
+	return node, mask
+}
+
+// Len returns the number of keys in the map.
+func (bm *BravoMap) Len() uint64 {
+	return bm.keys
+}
+
+// Nodes returns the number of nodes allocated by the map, including its root
+// node and any branches left behind by Delete.
+func (bm *BravoMap) Nodes() uint64 {
+	return bm.nodes
+}
+
+// SetPruneOnDelete controls whether Delete removes the entire branch that led
+// only to the deleted key, rather than only the leaf of the key. When it is
+// off, the branches left behind may be removed later by Compact.
+func (bm *BravoMap) SetPruneOnDelete(prune bool) {
+	bm.prune = prune
+}
+
+// Delete removes the specified 64-bit key and its value from the map.
+func (bm *BravoMap) Delete(key uint64) {
+	// The branch is cut below the deepest node that also leads somewhere
+	// other than to this key, or below the root node when there is none.
+	// Without pruning, only the leaf is cut from its parent.
+	fork, forkMask := bm.root, initialMask
+	node := bm.root
+	for mask := initialMask; mask != 0; mask >>= 1 {
+		if node.left != nil && node.right != nil || mask == 1 && !bm.prune {
+			fork, forkMask = node, mask
+		}
+		if key&mask != 0 {
+			node = node.right
+		} else {
+			node = node.left
+		}
+		if node == nil {
+			return // key not present
+		}
+	}
+
+	if key&forkMask != 0 {
+		fork.right = nil
+	} else {
+		fork.left = nil
+	}
+	bm.keys--
+	bm.nodes -= uint64(64 - bits.LeadingZeros64(forkMask))
+}
+
+// Compact removes every branch of the map that no longer leads to a key, such
+// as those left behind by Delete, and returns the number of nodes it removed.
+func (bm *BravoMap) Compact() (freed uint64) {
+	freed, _ = bm.root.compact(initialMask)
+	bm.nodes -= freed
+	return freed
+}
+
+// compact removes the branches below the node that do not lead to a key, where
+// mask is the bit of the key below the node, or 0 for a leaf. It returns the
+// number of nodes removed, and whether the node itself leads to a key. When it
+// does not, the node is included in the number removed, and its parent must
+// remove it.
+func (n *bmnode) compact(mask uint64) (uint64, bool) {
+	if mask == 0 {
+		return 0, true
+	}
+	var freed uint64
+	var live bool
+	if n.left != nil {
+		f, ok := n.left.compact(mask >> 1)
+		freed += f
+		if ok {
+			live = true
+		} else {
+			n.left = nil
+		}
+	}
+	if n.right != nil {
+		f, ok := n.right.compact(mask >> 1)
+		freed += f
+		if ok {
+			live = true
+		} else {
+			n.right = nil
+		}
+	}
+	if !live && mask != initialMask {
+		freed++ // the root node is never removed
+	}
+	return freed, live
+}
+
+// Load returns the value associated with the specified 64-bit key, along with
+// a boolean which is true when the map has the key.
+func (bm *BravoMap) Load(key uint64) (interface{}, bool) {
+	node, mask := bm.search(key)
+	if mask != 0 {
+		return nil, false
+	}
+	return *node.leaf, true
+}
+
+// Store stores the specified value for the specified 64-bit key.
+func (bm *BravoMap) Store(key uint64, value interface{}) {
+	// walk existing tree branches as much as possible
+	node, mask := bm.search(key)
+	if mask != 0 {
+		bm.keys++
+	}
+
+	// create whatever branches needed
+	for ; mask != 0; mask >>= 1 {
+		newNode := new(bmnode)
+		if mask == 1 {
+			newNode.leaf = new(interface{})
+		}
+		bm.nodes++
+		if key&mask != 0 {
+			node.right = newNode
+		} else {
+			node.left = newNode
+		}
+		node = newNode
+	}
+
+	*node.leaf = value
+}
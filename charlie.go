@@ -1,17 +1,33 @@
 package goradix
 
+import "time"
+
 // R-ary existence data structure
 
 type cnode struct {
 	children []*cnode
+	digits   []uint64 // digits that have children, in a sparse tree
+	word     uint64   // keys below the node when counting, or the bitmap of the keys ending at it
 }
 
 type Charlie struct {
-	head             *cnode
-	mask             uint64
-	childCount       uint64
-	Count            uint64
+	head       *cnode
+	mask       uint64
+	childCount uint64
+
+	// Count is incremented by Store and decremented by Delete, but by amounts
+	// that count neither keys nor nodes.
+	//
+	// Deprecated: Use Len for the number of keys, and Nodes for the number
+	// of nodes.
+	Count uint64
+
+	keys, nodes      uint64
 	bitInit, bitStep uint8 // these values computed once at init and used in most methods
+	observer         Observer
+	counting         bool  // each node has the number of keys below it
+	sparse           bool  // nodes only have children for the digits in use
+	leafBits         uint8 // final bits of each key kept in a bitmap, or 0
 }
 
 // var isPower2 = function(x) { return (x > 0 && !(x & (x-1))); };
@@ -53,16 +69,35 @@ func NewCharlie(bits uint8) *Charlie {
 		head:       &cnode{children: make([]*cnode, childCount)},
 		mask:       childCount - 1,
 		childCount: childCount,
+		nodes:      1,
 		bitInit:    64 - bits,
 		bitStep:    bits,
 	}
 }
 
+// Len returns the number of keys in the tree.
+func (tree *Charlie) Len() uint64 {
+	return tree.keys
+}
+
+// Nodes returns the number of nodes allocated by the tree, including its head
+// node and any branches left behind by Delete.
+func (tree *Charlie) Nodes() uint64 {
+	return tree.nodes
+}
+
 // find returns the node prior to a nil pointer, followed by one less from the
 // number of remaining bits to be shifted so upstream can determine whether key
 // was located. Bits will be 255 when the specified key was found. If the key
 // mismatched on the final bit, bits will be 0.
 func (tree *Charlie) find(key uint64) (*cnode, uint8) {
+	switch {
+	case tree.leafBits != 0:
+		return tree.findLeaf(key)
+	case tree.sparse:
+		return tree.findSparse(key)
+	}
+
 	var prev *cnode
 	curr := tree.head
 	bits := tree.bitInit
@@ -82,48 +117,121 @@ func (tree *Charlie) find(key uint64) (*cnode, uint8) {
 	return prev, bits
 }
 
+// findLeaf is find for a tree with bitmap leaves. When the path of the key is
+// complete, it returns the node that holds the bitmap of the key instead,
+// followed by 255 when the key is in the bitmap, or by 0 otherwise.
+func (tree *Charlie) findLeaf(key uint64) (*cnode, uint8) {
+	curr := tree.head
+	bits := tree.bitInit
+	for ; bits < 64 && bits >= tree.leafBits; bits -= tree.bitStep {
+		next := tree.child(curr, (key>>bits)&tree.mask)
+		if next == nil {
+			return curr, bits
+		}
+		curr = next
+	}
+	if curr.word&tree.leafBit(key) != 0 {
+		return curr, 255
+	}
+	return curr, 0
+}
+
+// visited returns the number of nodes visited by find, when it returned bits.
+func (tree *Charlie) visited(bits uint8) int {
+	if tree.leafBits != 0 && (bits >= 64 || bits < tree.leafBits) {
+		return tree.endLevel() + 1 // the path of the key was complete
+	}
+	// When the key was found, bits rolled over, and the difference is 64.
+	return int((tree.bitInit-bits)/tree.bitStep) + 1
+}
+
 // TODO: When heavy additions and removals, cleaning tree for every Delete
 // results in needless memory churn. Instead, provide a Compact method to clean
 // up dead branches independent of Delete method.
 
 // Delete removes the specified 64-bit key.
 func (tree *Charlie) Delete(key uint64) {
+	var start time.Time
+	if tree.observer != nil {
+		start = time.Now()
+	}
+
 	node, bits := tree.find(key)
-	if bits < 64 {
-		return // key not present
+	if bits >= 64 {
+		// key present
+		if tree.counting {
+			tree.adjustCounts(key, -1)
+		}
+		if tree.leafBits != 0 {
+			node.word &^= tree.leafBit(key)
+		} else {
+			tree.setChild(node, key&tree.mask, nil) // remove branch
+			tree.nodes--
+		}
+		tree.keys--
+		tree.Count--
+	}
+
+	if tree.observer != nil {
+		observe(tree.observer, start, &Observation{Op: OpDelete, Hit: bits >= 64, Nodes: tree.visited(bits)})
 	}
-	// Check the final bit to determine whether to remove right or left branch
-	// from node.
-	node.children[key&tree.mask] = nil // remove branch
-	tree.Count--
 }
 
 // Load returns whether or not the specified 64-bit key is present.
 func (tree *Charlie) Load(key uint64) bool {
+	if tree.observer != nil {
+		return tree.observedLoad(key)
+	}
 	_, bits := tree.find(key)
 	return bits >= 64 // key present when bits >= 64
 }
 
+// observedLoad is Load for a tree with an Observer.
+func (tree *Charlie) observedLoad(key uint64) bool {
+	start := time.Now()
+	_, bits := tree.find(key)
+	observe(tree.observer, start, &Observation{Op: OpLoad, Hit: bits >= 64, Nodes: tree.visited(bits)})
+	return bits >= 64
+}
+
 // Store stores the existence of the specified 64-bit key.
 func (tree *Charlie) Store(key uint64) {
+	var start time.Time
+	if tree.observer != nil {
+		start = time.Now()
+	}
+
 	// walk existing tree branches as much as possible
 	node, bits := tree.find(key)
-	if bits >= 64 {
-		return // key already present
-	}
+	hit, visited := bits >= 64, tree.visited(bits)
 
-	// create needed branches
-	tree.Count += uint64(bits + 1) // below loop adds this many nodes to tree
+	if !hit {
+		tree.keys++
 
-	childCount := tree.childCount // store in local variable so optimizer can see it never changes
-	mask := tree.mask             // store in local variable so optimizer can see it never changes
-	step := tree.bitStep          // store in local variable so optimizer can see it never changes
+		// create needed branches
+		tree.Count += uint64(bits + 1)
 
-	// Need to execute loop from start to 0 inclusive; therefore, terminate
-	// when rolls over down from 0 back up to 255.
-	for ; bits < 64; bits -= step {
-		newNode := &cnode{children: make([]*cnode, childCount)}
-		node.children[(key>>bits)&mask] = newNode
-		node = newNode
+		mask := tree.mask    // store in local variable so optimizer can see it never changes
+		step := tree.bitStep // store in local variable so optimizer can see it never changes
+
+		// Need to execute loop from start to 0 inclusive; therefore, terminate
+		// when rolls over down from 0 back up to 255. With bitmap leaves,
+		// terminate after the node that holds the bitmap.
+		for ; bits < 64 && bits >= tree.leafBits; bits -= step {
+			newNode := tree.newNode(bits)
+			tree.nodes++
+			tree.setChild(node, (key>>bits)&mask, newNode)
+			node = newNode
+		}
+		if tree.leafBits != 0 {
+			node.word |= tree.leafBit(key)
+		}
+		if tree.counting {
+			tree.adjustCounts(key, 1)
+		}
+	}
+
+	if tree.observer != nil {
+		observe(tree.observer, start, &Observation{Op: OpStore, Hit: hit, Nodes: visited})
 	}
 }
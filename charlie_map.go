@@ -0,0 +1,185 @@
+package goradix
+
+// cmnode is a node of a CharlieMap.
+type cmnode struct {
+	children []*cmnode
+	leaf     *interface{} // value of the key ending at the node, or nil above the leaves
+}
+
+// CharlieMap is a Charlie that associates a value with each of its 64-bit
+// keys.
+type CharlieMap struct {
+	head             *cmnode
+	mask             uint64
+	childCount       uint64
+	keys, nodes      uint64
+	bitInit, bitStep uint8 // these values computed once at init and used in most methods
+	prune            bool  // Delete removes the branch that led only to the key
+}
+
+// NewCharlieMap returns a new, empty map from 64-bit keys to values. The
+// specified bits must be a power of two greater than 0, such as 1, 2, 4, 8,
+// etc.
+func NewCharlieMap(bits uint8) *CharlieMap {
+	bits = roundToPowerOfTwo(bits)
+	childCount := uint64(1 << bits)
+	return &CharlieMap{
+		head:       &cmnode{children: make([]*cmnode, childCount)},
+		mask:       childCount - 1,
+		childCount: childCount,
+		nodes:      1,
+		bitInit:    64 - bits,
+		bitStep:    bits,
+	}
+}
+
+// find returns the node prior to a nil pointer, followed by one less from the
+// number of remaining bits to be shifted so upstream can determine whether key
+// was located. Bits will be 255 when the specified key was found, and the node
+// will be the leaf of the key.
+func (cm *CharlieMap) find(key uint64) (*cmnode, uint8) {
+	curr := cm.head
+	bits := cm.bitInit
+	mask := cm.mask    // store in local variable so optimizer can see it never changes
+	step := cm.bitStep // store in local variable so optimizer can see it never changes
+
+	// Need to execute loop from start to 0 inclusive; therefore, terminate when
+	// rolls over down from 0 back up to 255.
+	for ; bits < 64; bits -= step {
+		next := curr.children[(key>>bits)&mask]
+		if next == nil {
+			return curr, bits
+		}
+		curr = next
+	}
+
+	return curr, bits
+}
+
+// Len returns the number of keys in the map.
+func (cm *CharlieMap) Len() uint64 {
+	return cm.keys
+}
+
+// Nodes returns the number of nodes allocated by the map, including its head
+// node and any branches left behind by Delete.
+func (cm *CharlieMap) Nodes() uint64 {
+	return cm.nodes
+}
+
+// SetPruneOnDelete controls whether Delete removes the entire branch that led
+// only to the deleted key, rather than only the leaf of the key. When it is
+// off, the branches left behind may be removed later by Compact.
+func (cm *CharlieMap) SetPruneOnDelete(prune bool) {
+	cm.prune = prune
+}
+
+// Delete removes the specified 64-bit key and its value from the map.
+func (cm *CharlieMap) Delete(key uint64) {
+	// The branch is cut below the deepest node that also leads somewhere
+	// other than to this key, or below the head node when there is none.
+	// Without pruning, only the leaf is cut from its parent.
+	fork, forkBits := cm.head, cm.bitInit
+	node := cm.head
+	for bits := cm.bitInit; bits < 64; bits -= cm.bitStep {
+		i := (key >> bits) & cm.mask
+		if bits == 0 && !cm.prune || !node.onlyChild(i) {
+			fork, forkBits = node, bits
+		}
+		node = node.children[i]
+		if node == nil {
+			return // key not present
+		}
+	}
+
+	fork.children[(key>>forkBits)&cm.mask] = nil // remove branch
+	cm.keys--
+	cm.nodes -= uint64(forkBits/cm.bitStep) + 1
+}
+
+// onlyChild returns true when the node has no child other than its child at
+// index i.
+func (n *cmnode) onlyChild(i uint64) bool {
+	for j, child := range n.children {
+		if child != nil && uint64(j) != i {
+			return false
+		}
+	}
+	return true
+}
+
+// Compact removes every branch of the map that no longer leads to a key, such
+// as those left behind by Delete, and returns the number of nodes it removed.
+func (cm *CharlieMap) Compact() (freed uint64) {
+	freed, _ = cm.head.compact(cm.bitInit, cm.bitStep, true)
+	cm.nodes -= freed
+	return freed
+}
+
+// compact removes the branches below the node that do not lead to a key, where
+// bits is the shift of the digit of the key below the node, which rolls over
+// past 63 for a leaf. It returns the number of nodes removed, and whether the
+// node itself leads to a key. When it does not, the node is included in the
+// number removed, unless it is the head node, and its parent must remove it.
+func (n *cmnode) compact(bits, step uint8, head bool) (uint64, bool) {
+	if bits >= 64 {
+		return 0, true
+	}
+	var freed uint64
+	var live bool
+	for i, child := range n.children {
+		if child == nil {
+			continue
+		}
+		f, ok := child.compact(bits-step, step, false)
+		freed += f
+		if ok {
+			live = true
+		} else {
+			n.children[i] = nil
+		}
+	}
+	if !live && !head {
+		freed++ // the head node is never removed
+	}
+	return freed, live
+}
+
+// Load returns the value associated with the specified 64-bit key, along with
+// a boolean which is true when the map has the key.
+func (cm *CharlieMap) Load(key uint64) (interface{}, bool) {
+	node, bits := cm.find(key)
+	if bits < 64 {
+		return nil, false
+	}
+	return *node.leaf, true
+}
+
+// Store stores the specified value for the specified 64-bit key.
+func (cm *CharlieMap) Store(key uint64, value interface{}) {
+	// walk existing tree branches as much as possible
+	node, bits := cm.find(key)
+	if bits < 64 {
+		cm.keys++
+	}
+
+	childCount := cm.childCount // store in local variable so optimizer can see it never changes
+	mask := cm.mask             // store in local variable so optimizer can see it never changes
+	step := cm.bitStep          // store in local variable so optimizer can see it never changes
+
+	// Need to execute loop from start to 0 inclusive; therefore, terminate
+	// when rolls over down from 0 back up to 255.
+	for ; bits < 64; bits -= step {
+		var newNode *cmnode
+		if bits > 0 {
+			newNode = &cmnode{children: make([]*cmnode, childCount)}
+		} else {
+			newNode = &cmnode{leaf: new(interface{})}
+		}
+		cm.nodes++
+		node.children[(key>>bits)&mask] = newNode
+		node = newNode
+	}
+
+	*node.leaf = value
+}
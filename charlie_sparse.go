@@ -0,0 +1,216 @@
+package goradix
+
+import "math/bits"
+
+// maxSparseBits is the widest digit of a sparse tree.
+const maxSparseBits = 8
+
+// NewSparseCharlie returns a new tree like NewCharlie, but whose nodes only
+// have children for the digits in use. The specified bits need not be a power
+// of two, and are limited to between 1 and 8.
+func NewSparseCharlie(bits uint8) *Charlie {
+	switch {
+	case bits == 0:
+		bits = 1
+	case bits > maxSparseBits:
+		bits = maxSparseBits
+	}
+	childCount := uint64(1) << bits
+	tree := &Charlie{
+		mask:       childCount - 1,
+		childCount: childCount,
+		nodes:      1,
+		bitInit:    63 / bits * bits,
+		bitStep:    bits,
+		sparse:     true,
+	}
+	tree.head = &cnode{digits: make([]uint64, tree.childWords())}
+	return tree
+}
+
+// childWords returns the number of words in the bitmap of the digits of a node
+// of a sparse tree. The bitmap has a bit for every digit, however few of them
+// have children, which is why digits are limited to 8 bits: with 16, a node
+// with one child would need a bitmap of 1024 words. When the bits of a digit
+// do not divide 64, the first digit of each key is shorter than the others.
+func (tree *Charlie) childWords() uint64 {
+	return (tree.childCount + 63) / 64
+}
+
+// findSparse is find for a sparse tree.
+func (tree *Charlie) findSparse(key uint64) (*cnode, uint8) {
+	var prev *cnode
+	curr := tree.head
+	bits := tree.bitInit
+
+	// Need to execute loop from start to 0 inclusive; therefore, terminate when
+	// rolls over down from 0 back up to 255.
+	for ; bits < 64; bits -= tree.bitStep {
+		prev = curr
+		curr = tree.child(curr, (key>>bits)&tree.mask)
+		if curr == nil {
+			return prev, bits
+		}
+	}
+
+	return prev, bits
+}
+
+// rank returns the index in the children of a node of a sparse tree of the
+// child for the specified digit, which is the number of digits below it that
+// have children, according to the bitmap of the digits of the node. A node
+// keeps only the children it has, in order of their digits, so a node with one
+// child costs its bitmap and a single pointer rather than 2^bits pointers.
+func rank(bitmap []uint64, digit uint64) int {
+	w := digit / 64
+	r := bits.OnesCount64(bitmap[w] & (1<<(digit%64) - 1))
+	for _, word := range bitmap[:w] {
+		r += bits.OnesCount64(word)
+	}
+	return r
+}
+
+// child returns the child of the node, which is above the end level, for the
+// specified digit, or nil when it has none.
+func (tree *Charlie) child(n *cnode, digit uint64) *cnode {
+	if !tree.sparse {
+		return n.children[digit]
+	}
+	bitmap := n.digits
+	if bitmap[digit/64]&(1<<(digit%64)) == 0 {
+		return nil
+	}
+	return n.children[rank(bitmap, digit)]
+}
+
+// setChild sets the child of the node, which is above the end level, for the
+// specified digit, or removes it when child is nil. A node of a sparse tree
+// grows its children by one each time a child is added, so that it holds no
+// more than it uses.
+func (tree *Charlie) setChild(n *cnode, digit uint64, child *cnode) {
+	if !tree.sparse {
+		n.children[digit] = child
+		return
+	}
+
+	bitmap := n.digits
+	w, bit := digit/64, uint64(1)<<(digit%64)
+	i := rank(bitmap, digit)
+	switch {
+	case bitmap[w]&bit != 0 && child != nil:
+		n.children[i] = child
+	case bitmap[w]&bit != 0:
+		last := len(n.children) - 1
+		copy(n.children[i:], n.children[i+1:])
+		n.children[last] = nil
+		n.children = n.children[:last]
+		bitmap[w] &^= bit
+	case child != nil:
+		children := make([]*cnode, len(n.children)+1)
+		copy(children, n.children[:i])
+		children[i] = child
+		copy(children[i+1:], n.children[i:])
+		n.children = children
+		bitmap[w] |= bit
+	}
+}
+
+// eachChild invokes fn with the digit and child of each child of the node,
+// which is above the end level, in ascending order of digit, or in descending
+// order when reverse is true, until fn returns false. It returns false when fn
+// does. The children of a node of a sparse tree are taken in the order they
+// are kept, with their digits read from the bits set in its bitmap, so that a
+// node costs time in proportion to its children rather than to its digits.
+// When reverse is true, fn may remove the child it is given.
+func (tree *Charlie) eachChild(n *cnode, reverse bool, fn func(digit uint64, child *cnode) bool) bool {
+	if !tree.sparse {
+		count := tree.childCount
+		for i := uint64(0); i < count; i++ {
+			digit := i
+			if reverse {
+				digit = count - 1 - i
+			}
+			if child := n.children[digit]; child != nil && !fn(digit, child) {
+				return false
+			}
+		}
+		return true
+	}
+
+	bitmap := n.digits
+	if reverse {
+		i := len(n.children)
+		for w := len(bitmap) - 1; w >= 0; w-- {
+			for word := bitmap[w]; word != 0; {
+				b := 63 - bits.LeadingZeros64(word)
+				word &^= 1 << uint(b)
+				i--
+				if !fn(uint64(w)*64+uint64(b), n.children[i]) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	var i int
+	for w, word := range bitmap {
+		for ; word != 0; word &= word - 1 {
+			if !fn(uint64(w)*64+uint64(bits.TrailingZeros64(word)), n.children[i]) {
+				return false
+			}
+			i++
+		}
+	}
+	return true
+}
+
+// sibling returns the nearest child of the node, which is above the end level,
+// whose digit is greater than the specified digit, or less than it when
+// reverse is true, along with its digit. It returns nil when there is none.
+func (tree *Charlie) sibling(n *cnode, digit uint64, reverse bool) (uint64, *cnode) {
+	if !tree.sparse {
+		if reverse {
+			for d := digit - 1; d < digit; d-- { // until d rolls over
+				if child := n.children[d]; child != nil {
+					return d, child
+				}
+			}
+			return 0, nil
+		}
+		for d := digit + 1; d < tree.childCount; d++ {
+			if child := n.children[d]; child != nil {
+				return d, child
+			}
+		}
+		return 0, nil
+	}
+
+	// The children below i have digits less than digit, and the rest, apart
+	// from any child for digit itself, greater.
+	bitmap := n.digits
+	w, bit := digit/64, uint64(1)<<(digit%64)
+	i := rank(bitmap, digit)
+	if reverse {
+		if i == 0 {
+			return 0, nil
+		}
+		word := bitmap[w] & (bit - 1)
+		for word == 0 {
+			w--
+			word = bitmap[w]
+		}
+		return w*64 + uint64(63-bits.LeadingZeros64(word)), n.children[i-1]
+	}
+	if bitmap[w]&bit != 0 {
+		i++
+	}
+	if i == len(n.children) {
+		return 0, nil
+	}
+	word := bitmap[w] &^ (bit<<1 - 1) // bit<<1 is 0 for the final digit of a word
+	for word == 0 {
+		w++
+		word = bitmap[w]
+	}
+	return w*64 + uint64(bits.TrailingZeros64(word)), n.children[i]
+}
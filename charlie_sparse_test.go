@@ -0,0 +1,220 @@
+package goradix
+
+import (
+	"math/bits"
+	"math/rand"
+	"testing"
+	"unsafe"
+)
+
+func TestCharlieSetChild(t *testing.T) {
+	tree := NewSparseCharlie(8)
+	n := tree.head
+	a, b, c := new(cnode), new(cnode), new(cnode)
+
+	tree.setChild(n, 200, a)
+	tree.setChild(n, 3, b)
+	tree.setChild(n, 64, c)
+	tree.setChild(n, 7, nil)
+	if got, want := len(n.children), 3; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	for _, tc := range []struct {
+		digit uint64
+		want  *cnode
+	}{{3, b}, {64, c}, {200, a}, {0, nil}, {63, nil}, {255, nil}} {
+		if got := tree.child(n, tc.digit); got != tc.want {
+			t.Errorf("digit: %d; GOT: %p; WANT: %p", tc.digit, got, tc.want)
+		}
+	}
+
+	tree.setChild(n, 64, a)
+	tree.setChild(n, 3, nil)
+	if got, want := tree.child(n, 64), a; got != want {
+		t.Errorf("GOT: %p; WANT: %p", got, want)
+	}
+	if got, want := tree.child(n, 3), (*cnode)(nil); got != want {
+		t.Errorf("GOT: %p; WANT: %p", got, want)
+	}
+	if got, want := len(n.children), 2; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+// checkSparse checks that each node below and including the node, which is at
+// the specified level, has exactly one child for each bit of its bitmap.
+func checkSparse(t *testing.T, tree *Charlie, n *cnode, level int) {
+	if level == tree.endLevel() {
+		if n.children != nil {
+			t.Errorf("level: %d; GOT: %v; WANT: a leaf", level, n.children)
+		}
+		return
+	}
+	var count int
+	for _, word := range n.digits {
+		count += bits.OnesCount64(word)
+	}
+	if got, want := len(n.children), count; got != want {
+		t.Errorf("level: %d; GOT: %v; WANT: %v", level, got, want)
+	}
+	for _, child := range n.children {
+		if child == nil {
+			t.Errorf("level: %d; GOT: nil child", level)
+			continue
+		}
+		checkSparse(t, tree, child, level+1)
+	}
+}
+
+func TestSparseCharlie(t *testing.T) {
+	for _, width := range []uint8{1, 2, 4, 6, 8} {
+		dense, sparse := NewCharlie(width), NewSparseCharlie(width)
+		rng := rand.New(rand.NewSource(1))
+
+		var keys []uint64
+		for i := 0; i < 500; i++ {
+			keys = append(keys, rng.Uint64(), uint64(rng.Intn(1000)))
+		}
+		for _, key := range keys {
+			dense.Store(key)
+			sparse.Store(key)
+		}
+		for i, key := range keys {
+			if i%3 == 0 {
+				dense.Delete(key)
+				sparse.Delete(key)
+			}
+		}
+
+		for _, key := range keys {
+			for _, k := range []uint64{key, key ^ 1, key ^ 1<<40} {
+				if got, want := sparse.Load(k), dense.Load(k); got != want {
+					t.Errorf("bits: %d; Load(%#x): GOT: %v; WANT: %v", width, k, got, want)
+				}
+			}
+		}
+		if got, want := sparse.Len(), dense.Len(); got != want {
+			t.Errorf("bits: %d; GOT: %v; WANT: %v", width, got, want)
+		}
+		if got, want := sparse.Nodes(), dense.Nodes(); width != 6 && got != want {
+			t.Errorf("bits: %d; GOT: %v; WANT: %v", width, got, want)
+		}
+		if got, want := sparse.Stats().HeapBytes, dense.Stats().HeapBytes; width >= 4 && got >= want {
+			t.Errorf("bits: %d; GOT: %v; WANT: less than %v", width, got, want)
+		}
+		checkSparse(t, sparse, sparse.head, 0)
+	}
+}
+
+func TestSparseCharlieSixBits(t *testing.T) {
+	tree := NewSparseCharlie(6)
+	if got, want := tree.endLevel(), 11; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	// The head has a child for the first 4 bits of the key, and each node
+	// below it for 6 more. Each node above the leaf keeps its bitmap in a
+	// single word, and one child.
+	key := uint64(0xfedcba9876543210)
+	tree.Store(key)
+	var n cnode
+	node := uint64(unsafe.Sizeof(n)) + 8 + uint64(unsafe.Sizeof(&n))
+	if got, want := tree.Stats().HeapBytes, uint64(unsafe.Sizeof(*tree))+11*node+uint64(unsafe.Sizeof(n)); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := tree.child(tree.head, key>>60), tree.head.children[0]; got != want {
+		t.Errorf("GOT: %p; WANT: %p", got, want)
+	}
+	for _, k := range []uint64{key, key ^ 1, key ^ 1<<59, key ^ 1<<63} {
+		if got, want := tree.Load(k), k == key; got != want {
+			t.Errorf("Load(%#x): GOT: %v; WANT: %v", k, got, want)
+		}
+	}
+}
+
+func TestSparseCharlieWidest(t *testing.T) {
+	for _, width := range []uint8{maxSparseBits, maxSparseBits + 1, 16, 32, 255} {
+		tree := NewSparseCharlie(width)
+		if got, want := tree.bitStep, uint8(maxSparseBits); got != want {
+			t.Errorf("bits: %d; GOT: %v; WANT: %v", width, got, want)
+		}
+		if got, want := tree.childWords(), uint64(4); got != want {
+			t.Errorf("bits: %d; GOT: %v; WANT: %v", width, got, want)
+		}
+
+		keys := []uint64{0, 1, 0xff, 0x100, 0xfedcba9876543210, ^uint64(0)}
+		for _, key := range keys {
+			tree.Store(key)
+		}
+		for _, key := range keys {
+			for _, k := range []uint64{key, key ^ 1<<20} {
+				if got, want := tree.Load(k), k == key; got != want {
+					t.Errorf("bits: %d; Load(%#x): GOT: %v; WANT: %v", width, k, got, want)
+				}
+			}
+		}
+		if got, want := tree.Len(), uint64(len(keys)); got != want {
+			t.Errorf("bits: %d; GOT: %v; WANT: %v", width, got, want)
+		}
+		checkSparse(t, tree, tree.head, 0)
+	}
+}
+
+func TestSparseCharlieChildren(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, width := range []uint8{1, 6, 7, 8} {
+		tree := NewSparseCharlie(width)
+		n := tree.head
+		want := make([]*cnode, tree.childCount)
+		for i := 0; i < 20; i++ {
+			digit := uint64(rng.Intn(int(tree.childCount)))
+			want[digit] = new(cnode)
+			tree.setChild(n, digit, want[digit])
+		}
+
+		for _, reverse := range []bool{false, true} {
+			var got []uint64
+			tree.eachChild(n, reverse, func(digit uint64, child *cnode) bool {
+				if child != want[digit] {
+					t.Errorf("bits: %d; digit: %d; GOT: %p; WANT: %p", width, digit, child, want[digit])
+				}
+				got = append(got, digit)
+				return true
+			})
+			for i := 1; i < len(got); i++ {
+				if (got[i-1] < got[i]) == reverse {
+					t.Errorf("bits: %d; reverse: %v; GOT: %v", width, reverse, got)
+					break
+				}
+			}
+			if got, want := len(got), len(n.children); got != want {
+				t.Errorf("bits: %d; GOT: %v; WANT: %v", width, got, want)
+			}
+		}
+
+		for digit := uint64(0); digit < tree.childCount; digit++ {
+			var next, prev *cnode
+			for d := digit + 1; d < tree.childCount && next == nil; d++ {
+				next = want[d]
+			}
+			for d := digit - 1; d < digit && prev == nil; d-- {
+				prev = want[d]
+			}
+			if d, got := tree.sibling(n, digit, false); got != next || got != nil && want[d] != got {
+				t.Errorf("bits: %d; digit: %d; GOT: %d %p; WANT: %p", width, digit, d, got, next)
+			}
+			if d, got := tree.sibling(n, digit, true); got != prev || got != nil && want[d] != got {
+				t.Errorf("bits: %d; digit: %d; GOT: %d %p; WANT: %p", width, digit, d, got, prev)
+			}
+		}
+
+		// Removing each child as it is visited in reverse leaves none.
+		tree.eachChild(n, true, func(digit uint64, child *cnode) bool {
+			tree.setChild(n, digit, nil)
+			return true
+		})
+		if got, want := len(n.children), 0; got != want {
+			t.Errorf("bits: %d; GOT: %v; WANT: %v", width, got, want)
+		}
+	}
+}
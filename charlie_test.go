@@ -63,12 +63,12 @@ func TestCharlie2Count(t *testing.T) {
 	var thresh = 1
 	for i := 0; i < limit; i++ {
 		if i%thresh == 0 {
-			log.Printf("n: %12d; count: %20d; bytes: %12d", i, tree.Count, tree.Count*(24+tree.childCount*8))
+			log.Printf("n: %12d; count: %20d; bytes: %12d", i, tree.Nodes(), tree.Nodes()*(24+tree.childCount*8))
 			thresh *= 10
 		}
 		tree.Store(rand.Uint64())
 	}
-	log.Printf("n: %12d; count: %20d; bytes: %12d", limit, tree.Count, tree.Count*(24+tree.childCount*8))
+	log.Printf("n: %12d; count: %20d; bytes: %12d", limit, tree.Nodes(), tree.Nodes()*(24+tree.childCount*8))
 }
 
 func TestCharlie4Count(t *testing.T) {
@@ -79,12 +79,12 @@ func TestCharlie4Count(t *testing.T) {
 	var thresh = 1
 	for i := 0; i < limit; i++ {
 		if i%thresh == 0 {
-			log.Printf("n: %12d; count: %20d; bytes: %12d", i, tree.Count, tree.Count*(24+tree.childCount*8))
+			log.Printf("n: %12d; count: %20d; bytes: %12d", i, tree.Nodes(), tree.Nodes()*(24+tree.childCount*8))
 			thresh *= 10
 		}
 		tree.Store(rand.Uint64())
 	}
-	log.Printf("n: %12d; count: %20d; bytes: %12d", limit, tree.Count, tree.Count*(24+tree.childCount*8))
+	log.Printf("n: %12d; count: %20d; bytes: %12d", limit, tree.Nodes(), tree.Nodes()*(24+tree.childCount*8))
 }
 
 func TestCharlie8Count(t *testing.T) {
@@ -95,10 +95,36 @@ func TestCharlie8Count(t *testing.T) {
 	var thresh = 1
 	for i := 0; i < limit; i++ {
 		if i%thresh == 0 {
-			log.Printf("n: %12d; count: %20d; bytes: %12d", i, tree.Count, tree.Count*(24+tree.childCount*8))
+			log.Printf("n: %12d; count: %20d; bytes: %12d", i, tree.Nodes(), tree.Nodes()*(24+tree.childCount*8))
 			thresh *= 10
 		}
 		tree.Store(rand.Uint64())
 	}
-	log.Printf("n: %12d; count: %20d; bytes: %12d", limit, tree.Count, tree.Count*(24+tree.childCount*8))
+	log.Printf("n: %12d; count: %20d; bytes: %12d", limit, tree.Nodes(), tree.Nodes()*(24+tree.childCount*8))
+}
+
+func TestCharlieLenAndNodes(t *testing.T) {
+	for _, bits := range []uint8{1, 2, 4, 8} {
+		rng := rand.New(rand.NewSource(int64(bits)))
+		tree := NewCharlie(bits)
+		live := make(map[uint64]struct{})
+
+		for i := 0; i < 2000; i++ {
+			key := uint64(rng.Intn(256)) << uint(rng.Intn(56))
+			if rng.Intn(3) == 0 {
+				tree.Delete(key)
+				delete(live, key)
+			} else {
+				tree.Store(key)
+				live[key] = struct{}{}
+			}
+		}
+
+		if got, want := tree.Len(), uint64(len(live)); got != want {
+			t.Errorf("bits: %d; Len: GOT: %v; WANT: %v", bits, got, want)
+		}
+		if got, want := tree.Nodes(), tree.Stats().Nodes; got != want {
+			t.Errorf("bits: %d; Nodes: GOT: %v; WANT: %v", bits, got, want)
+		}
+	}
 }
@@ -0,0 +1,124 @@
+package goradix
+
+import "math/bits"
+
+// dnode is a node of a Delta. A leaf has no children, and holds a whole key.
+// An inner node always has two children, and tests the bit of the key at
+// position bit, counting from the least significant bit. Its key holds the
+// bits above that position, which every key below it has in common.
+type dnode struct {
+	children [2]*dnode
+	key      uint64
+	bit      uint8
+}
+
+// isLeaf returns true when the node holds a key rather than tests a bit.
+func (n *dnode) isLeaf() bool {
+	return n.children[0] == nil
+}
+
+// above returns the bits of key above the specified position.
+func above(key uint64, bit uint8) uint64 {
+	return key &^ (1<<(uint(bit)+1) - 1)
+}
+
+// Delta is a path-compressed, or PATRICIA, variant of Bravo. Rather than one
+// node for every bit of every key, it only has a node at each bit where the
+// keys below it differ, followed by a leaf for each key, so that it holds n
+// keys in 2n-1 nodes.
+type Delta struct {
+	root        *dnode
+	keys, nodes uint64
+}
+
+// NewDelta returns a new, empty Delta.
+func NewDelta() *Delta {
+	return new(Delta)
+}
+
+// Len returns the number of keys in the trie.
+func (dt *Delta) Len() uint64 {
+	return dt.keys
+}
+
+// Nodes returns the number of nodes allocated by the trie.
+func (dt *Delta) Nodes() uint64 {
+	return dt.nodes
+}
+
+// Delete removes the specified 64-bit key from the trie.
+func (dt *Delta) Delete(key uint64) {
+	// Remember the pointer to the node, and to its parent, so that the
+	// sibling of the leaf can take the place of the parent.
+	var parent **dnode
+	node := &dt.root
+	for *node != nil && !(*node).isLeaf() {
+		n := *node
+		if above(key, n.bit) != n.key {
+			return // key not present
+		}
+		parent, node = node, &n.children[(key>>n.bit)&1]
+	}
+	if *node == nil || (*node).key != key {
+		return // key not present
+	}
+
+	if parent == nil {
+		*node = nil
+		dt.nodes--
+	} else {
+		n := *parent
+		*parent = n.children[1-(key>>n.bit)&1]
+		dt.nodes -= 2
+	}
+	dt.keys--
+}
+
+// Load returns whether or not the specified 64-bit key is present in the trie.
+func (dt *Delta) Load(key uint64) bool {
+	n := dt.root
+	for n != nil && !n.isLeaf() {
+		if above(key, n.bit) != n.key {
+			return false
+		}
+		n = n.children[(key>>n.bit)&1]
+	}
+	return n != nil && n.key == key
+}
+
+// Store stores the existence of the specified 64-bit key.
+func (dt *Delta) Store(key uint64) {
+	// Descend until reaching a leaf, or an inner node whose keys differ from
+	// key above the bit it tests. The new inner node goes in its place.
+	node := &dt.root
+	for *node != nil {
+		n := *node
+		if n.isLeaf() {
+			if n.key == key {
+				return // key already present
+			}
+			break
+		}
+		if above(key, n.bit) != n.key {
+			break
+		}
+		node = &n.children[(key>>n.bit)&1]
+	}
+
+	leaf := &dnode{key: key}
+	dt.keys++
+	dt.nodes++
+	if *node == nil {
+		*node = leaf // empty trie
+		return
+	}
+
+	// The highest bit where key differs from the keys below node.
+	bit := uint8(63 - bits.LeadingZeros64(key^(*node).key))
+	inner := &dnode{key: above(key, bit), bit: bit}
+	dt.nodes++
+	side := (key >> bit) & 1
+	inner.children[side] = leaf
+	inner.children[1-side] = *node
+	*node = inner
+}
@@ -0,0 +1,109 @@
+package goradix
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestDeltaStore(t *testing.T) {
+	dt := NewDelta()
+
+	if got, want := dt.Load(0), false; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	dt.Store(8)
+	dt.Store(0xFFFE)
+	dt.Store(8)
+
+	if got, want := dt.Load(9), false; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dt.Load(8), true; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dt.Load(0xFFFF), false; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dt.Load(0xFFFE), true; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dt.Len(), uint64(2); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dt.Nodes(), uint64(3); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestDeltaDelete(t *testing.T) {
+	dt := NewDelta()
+
+	dt.Store(8)
+	dt.Store(1 << 63)
+	dt.Store(9)
+
+	dt.Delete(10)
+	dt.Delete(1<<63 | 8)
+	if got, want := dt.Len(), uint64(3); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	dt.Delete(8)
+	if got, want := dt.Load(8), false; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dt.Load(9), true; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dt.Nodes(), uint64(3); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	dt.Delete(9)
+	dt.Delete(1 << 63)
+	if got, want := dt.Load(1<<63), false; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dt.Len(), uint64(0); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dt.Nodes(), uint64(0); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestDeltaRandom(t *testing.T) {
+	dt := NewDelta()
+	oracle := make(map[uint64]struct{})
+	r := rand.New(rand.NewSource(1))
+
+	// Few distinct high bits, so that keys share long prefixes.
+	key := func() uint64 { return r.Uint64() & 0xF00000000000000F }
+
+	for i := 0; i < 10000; i++ {
+		k := key()
+		if r.Intn(3) == 0 {
+			dt.Delete(k)
+			delete(oracle, k)
+		} else {
+			dt.Store(k)
+			oracle[k] = struct{}{}
+		}
+		k = key()
+		if _, want := oracle[k]; dt.Load(k) != want {
+			t.Fatalf("key: %#x; GOT: %v; WANT: %v", k, !want, want)
+		}
+	}
+	if got, want := dt.Len(), uint64(len(oracle)); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dt.Nodes(), 2*dt.Len()-1; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	for k := range oracle {
+		if !dt.Load(k) {
+			t.Errorf("key: %#x; GOT: %v; WANT: %v", k, false, true)
+		}
+	}
+}
@@ -0,0 +1,308 @@
+package goradix
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DOTOptions controls which part of a Trie is written by WriteDOT.
+type DOTOptions struct {
+	// Prefix limits the output of an Alpha to the subtree of keys that have
+	// this prefix.
+	Prefix string
+
+	// KeyPrefix and KeyPrefixBits limit the output of a Bravo or Charlie to the
+	// subtree of keys whose most significant KeyPrefixBits bits are the same
+	// as those of KeyPrefix. For Charlie, KeyPrefixBits is rounded down to a
+	// whole number of digits.
+	KeyPrefix     uint64
+	KeyPrefixBits uint8
+
+	// MaxDepth limits the output to nodes no more than this many edges below
+	// the first node written. Nodes whose children were left out are drawn
+	// with a dashed outline. When 0, the depth is not limited.
+	MaxDepth int
+}
+
+// dotWriter writes the nodes and edges of a Graphviz DOT graph, keeping the
+// first error returned by its io.Writer.
+type dotWriter struct {
+	bw       *bufio.Writer
+	maxDepth int
+	nodes    int
+}
+
+func newDOTWriter(w io.Writer, maxDepth int) *dotWriter {
+	dw := &dotWriter{bw: bufio.NewWriter(w), maxDepth: maxDepth}
+	dw.bw.WriteString("digraph trie {\n\tnode [shape=circle, label=\"\"];\n")
+	return dw
+}
+
+// truncated returns true when the children of a node at the specified depth
+// are not to be written.
+func (dw *dotWriter) truncated(depth int) bool {
+	return dw.maxDepth > 0 && depth >= dw.maxDepth
+}
+
+// node writes a new node, and returns its identifier. Keys are drawn with a
+// double outline.
+func (dw *dotWriter) node(label string, isKey, truncated bool) int {
+	id := dw.nodes
+	dw.nodes++
+	var attributes []string
+	if label != "" {
+		attributes = append(attributes, "label="+dotQuote(label))
+	}
+	if isKey {
+		attributes = append(attributes, "shape=doublecircle")
+	}
+	if truncated {
+		attributes = append(attributes, "style=dashed")
+	}
+	if len(attributes) == 0 {
+		fmt.Fprintf(dw.bw, "\tn%d;\n", id)
+	} else {
+		fmt.Fprintf(dw.bw, "\tn%d [%s];\n", id, strings.Join(attributes, ", "))
+	}
+	return id
+}
+
+func (dw *dotWriter) edge(from, to int, label string) {
+	fmt.Fprintf(dw.bw, "\tn%d -> n%d [label=%s];\n", from, to, dotQuote(label))
+}
+
+// dotQuote returns s as a quoted DOT string. Only the quote and the backslash
+// are escaped with a backslash, for DOT has no other escapes. Since Graphviz
+// reads HTML entities in labels, the ampersand is written as an entity, as are
+// control characters, and each byte that is not part of valid UTF-8 is written
+// as the replacement character. Every other rune is written as is.
+func dotQuote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '"' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r == '&':
+			sb.WriteString("&amp;")
+		case r == utf8.RuneError && size == 1:
+			sb.WriteString("&#xFFFD;")
+		case unicode.IsControl(r):
+			sb.WriteString("&#" + strconv.Itoa(int(r)) + ";")
+		default:
+			sb.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func (dw *dotWriter) close() error {
+	dw.bw.WriteString("}\n")
+	return dw.bw.Flush()
+}
+
+// WriteDOT writes the structure of the Trie to w as a Graphviz DOT graph. Each
+// edge is labeled with the prefix of the node below it, and each node that
+// holds a value is drawn with a double outline and labeled with its value.
+func (tn *Alpha) WriteDOT(w io.Writer, opts DOTOptions) error {
+	dw := newDOTWriter(w, opts.MaxDepth)
+
+	prefix := opts.Prefix
+	if fold := tn.folder(); fold != nil {
+		prefix = fold(prefix)
+	}
+	if curr, extra := tn.keysFindStartingNode(prefix, nil); curr != nil {
+		if extra > 0 {
+			// The prefix ends inside this node, so draw the edge to it from a
+			// node that stands for the prefix.
+			parent := dw.node(`"`+prefix+`"`, false, false)
+			curr.dot(dw, parent, curr.prefix[len(curr.prefix)-extra:], 1)
+		} else {
+			curr.dot(dw, -1, `"`+prefix+`"`, 0)
+		}
+	}
+
+	return dw.close()
+}
+
+// dot writes the node, and the nodes below it, with an edge from the parent
+// node, when there is one. When there is no parent, label is the label of the
+// node rather than of the edge.
+func (tn *Alpha) dot(dw *dotWriter, parent int, label string, depth int) {
+	children := tn.children
+	var value interface{}
+	var isKey bool
+	if len(children) > 0 && children[0].prefix == "" {
+		value, isKey = children[0].value, true
+		if fv, ok := value.(*foldedValue); ok {
+			value = fv.value
+		}
+		children = children[1:]
+	}
+
+	truncated := len(children) > 0 && dw.truncated(depth)
+	var nodeLabel string
+	switch {
+	case parent < 0:
+		nodeLabel = label
+		if isKey {
+			nodeLabel += fmt.Sprintf(" = %v", value)
+		}
+	case isKey:
+		nodeLabel = fmt.Sprintf("%v", value)
+	}
+	id := dw.node(nodeLabel, isKey, truncated)
+	if parent >= 0 {
+		dw.edge(parent, id, label)
+	}
+
+	if truncated {
+		return
+	}
+	for _, child := range children {
+		child.dot(dw, id, child.prefix, depth+1)
+	}
+}
+
+// WriteDOT writes the structure of the DST to w as a Graphviz DOT graph. Each
+// edge is labeled with the bit of the key it stands for, and each node at the
+// end of a key is drawn with a double outline and labeled with that key. With
+// bitmap leaves, such a node is labeled with the bits of the path to it,
+// followed by its bitmap.
+func (dst *Bravo) WriteDOT(w io.Writer, opts DOTOptions) error {
+	dw := newDOTWriter(w, opts.MaxDepth)
+
+	node := dst.root
+	var key uint64
+	mask := initialMask
+	depth := int(opts.KeyPrefixBits)
+	if end := 64 - int(dst.leafBits); depth > end {
+		depth = end
+	}
+	for i := 0; i < depth && node != nil; i++ {
+		if opts.KeyPrefix&mask != 0 {
+			key |= mask
+			node = node.right
+		} else {
+			node = node.left
+		}
+		mask >>= 1
+	}
+	if node != nil {
+		node.dot(dw, -1, "", key, mask, dst.tail(), depth, 0)
+	}
+
+	return dw.close()
+}
+
+// dot writes the node, and the nodes below it, where key holds the bits of the
+// path to the node, and mask is the bit of the key below the node.
+func (n *bnode) dot(dw *dotWriter, parent int, label string, key, mask, tail uint64, depth, written int) {
+	hasChildren := n.left != nil || n.right != nil
+	truncated := hasChildren && dw.truncated(written)
+
+	var nodeLabel string
+	var isKey bool
+	switch {
+	case mask > tail:
+	case tail == 0:
+		nodeLabel, isKey = fmt.Sprintf("%#x", key), true
+	default:
+		bitmap := n.leaves(tail)
+		nodeLabel, isKey = fmt.Sprintf("%#x %016x", key, bitmap), bitmap != 0
+	}
+	id := dw.node(nodeLabel, isKey, truncated)
+	if parent >= 0 {
+		dw.edge(parent, id, label)
+	}
+
+	if truncated {
+		return
+	}
+	if n.left != nil {
+		n.left.dot(dw, id, "0", key, mask>>1, tail, depth+1, written+1)
+	}
+	if n.right != nil {
+		n.right.dot(dw, id, "1", key|mask, mask>>1, tail, depth+1, written+1)
+	}
+}
+
+// WriteDOT writes the structure of the tree to w as a Graphviz DOT graph. Each
+// edge is labeled with the hexadecimal digit of the key it stands for, and each
+// node at the end of a key is drawn with a double outline and labeled with
+// that key. With bitmap leaves, such a node is labeled with the digits of the
+// path to it, followed by the words of its bitmap.
+func (tree *Charlie) WriteDOT(w io.Writer, opts DOTOptions) error {
+	dw := newDOTWriter(w, opts.MaxDepth)
+
+	node := tree.head
+	var key uint64
+	bits := tree.bitInit
+	end := tree.endLevel()
+	var depth int
+	if first := 64 - int(tree.bitInit); int(opts.KeyPrefixBits) >= first {
+		depth = (int(opts.KeyPrefixBits)-first)/int(tree.bitStep) + 1
+	}
+	if depth > end {
+		depth = end
+	}
+	for i := 0; i < depth && node != nil; i++ {
+		digit := (opts.KeyPrefix >> bits) & tree.mask
+		key |= digit << bits
+		node = tree.child(node, digit)
+		bits -= tree.bitStep
+	}
+	if node != nil {
+		tree.dot(dw, node, -1, "", key, bits, depth, 0)
+	}
+
+	return dw.close()
+}
+
+// dot writes the node, and the nodes below it, where key holds the digits of
+// the path to the node, and bits is the shift of the digit of the key below
+// the node.
+func (tree *Charlie) dot(dw *dotWriter, n *cnode, parent int, label string, key uint64, bits uint8, depth, written int) {
+	end := tree.endLevel()
+	var hasChildren bool
+	if depth < end {
+		for _, child := range n.children {
+			if child != nil {
+				hasChildren = true
+				break
+			}
+		}
+	}
+	truncated := hasChildren && dw.truncated(written)
+
+	var nodeLabel string
+	var isKey bool
+	switch {
+	case depth < end:
+	case tree.leafBits == 0:
+		nodeLabel, isKey = fmt.Sprintf("%#x", key), true
+	default:
+		nodeLabel, isKey = fmt.Sprintf("%#x %x", key, n.word), tree.hasChild(n, depth)
+	}
+	id := dw.node(nodeLabel, isKey, truncated)
+	if parent >= 0 {
+		dw.edge(parent, id, label)
+	}
+
+	if !hasChildren || truncated {
+		return
+	}
+	tree.eachChild(n, false, func(digit uint64, child *cnode) bool {
+		tree.dot(dw, child, id, fmt.Sprintf("%x", digit), key|digit<<bits, bits-tree.bitStep, depth+1, written+1)
+		return true
+	})
+}
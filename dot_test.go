@@ -0,0 +1,187 @@
+package goradix
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAlphaWriteDOT(t *testing.T) {
+	trie := new(Alpha)
+	trie.Store("sam", 1)
+	trie.Store("samuel", 2)
+	trie.Store("sally", 3)
+
+	cases := []struct {
+		name string
+		opts DOTOptions
+		want string
+	}{
+		{
+			name: "all",
+			want: `digraph trie {
+	node [shape=circle, label=""];
+	n0 [label="\"\""];
+	n1;
+	n0 -> n1 [label="sa"];
+	n2 [label="3", shape=doublecircle];
+	n1 -> n2 [label="lly"];
+	n3 [label="1", shape=doublecircle];
+	n1 -> n3 [label="m"];
+	n4 [label="2", shape=doublecircle];
+	n3 -> n4 [label="uel"];
+}
+`,
+		},
+		{
+			name: "prefix inside node",
+			opts: DOTOptions{Prefix: "s"},
+			want: `digraph trie {
+	node [shape=circle, label=""];
+	n0 [label="\"s\""];
+	n1;
+	n0 -> n1 [label="a"];
+	n2 [label="3", shape=doublecircle];
+	n1 -> n2 [label="lly"];
+	n3 [label="1", shape=doublecircle];
+	n1 -> n3 [label="m"];
+	n4 [label="2", shape=doublecircle];
+	n3 -> n4 [label="uel"];
+}
+`,
+		},
+		{
+			name: "prefix and depth",
+			opts: DOTOptions{Prefix: "sa", MaxDepth: 1},
+			want: `digraph trie {
+	node [shape=circle, label=""];
+	n0 [label="\"sa\""];
+	n1 [label="3", shape=doublecircle];
+	n0 -> n1 [label="lly"];
+	n2 [label="1", shape=doublecircle, style=dashed];
+	n0 -> n2 [label="m"];
+}
+`,
+		},
+		{
+			name: "prefix not found",
+			opts: DOTOptions{Prefix: "x"},
+			want: `digraph trie {
+	node [shape=circle, label=""];
+}
+`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var bb bytes.Buffer
+			if err := trie.WriteDOT(&bb, c.opts); err != nil {
+				t.Fatal(err)
+			}
+			if got := bb.String(); got != c.want {
+				t.Errorf("\nGOT:\n%s\nWANT:\n%s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestAlphaWriteDOTNonASCII(t *testing.T) {
+	trie := new(Alpha)
+	trie.Store("café", `a "b" \c`)
+	trie.Store("cafè", "x\x00&y")
+	trie.Store("naïve", "ü")
+
+	var bb bytes.Buffer
+	if err := trie.WriteDOT(&bb, DOTOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both keys share the first byte of their final rune, so the nodes below
+	// it are labeled with the bytes that are left of each rune.
+	want := `digraph trie {
+	node [shape=circle, label=""];
+	n0 [label="\"\""];
+	n1;
+	n0 -> n1 [label="caf&#xFFFD;"];
+	n2 [label="x&#0;&amp;y", shape=doublecircle];
+	n1 -> n2 [label="&#xFFFD;"];
+	n3 [label="a \"b\" \\c", shape=doublecircle];
+	n1 -> n3 [label="&#xFFFD;"];
+	n4 [label="ü", shape=doublecircle];
+	n0 -> n4 [label="naïve"];
+}
+`
+	if got := bb.String(); got != want {
+		t.Errorf("\nGOT:\n%s\nWANT:\n%s", got, want)
+	}
+}
+
+func TestDOTQuote(t *testing.T) {
+	cases := []struct {
+		s, want string
+	}{
+		{"", `""`},
+		{"café", `"café"`},
+		{"日本", `"日本"`},
+		{`a"b\c`, `"a\"b\\c"`},
+		{"a&lt;", `"a&amp;lt;"`},
+		{"\a\n\x00", `"&#7;&#10;&#0;"`},
+		{"\xc3", `"&#xFFFD;"`},
+	}
+	for _, c := range cases {
+		if got := dotQuote(c.s); got != c.want {
+			t.Errorf("dotQuote(%q): GOT: %s; WANT: %s", c.s, got, c.want)
+		}
+	}
+}
+
+func TestBravoWriteDOT(t *testing.T) {
+	dst := NewBravo()
+	dst.Store(2)
+	dst.Store(3)
+
+	var bb bytes.Buffer
+	if err := dst.WriteDOT(&bb, DOTOptions{KeyPrefix: 2, KeyPrefixBits: 62}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `digraph trie {
+	node [shape=circle, label=""];
+	n0;
+	n1;
+	n0 -> n1 [label="1"];
+	n2 [label="0x2", shape=doublecircle];
+	n1 -> n2 [label="0"];
+	n3 [label="0x3", shape=doublecircle];
+	n1 -> n3 [label="1"];
+}
+`
+	if got := bb.String(); got != want {
+		t.Errorf("\nGOT:\n%s\nWANT:\n%s", got, want)
+	}
+}
+
+func TestCharlieWriteDOT(t *testing.T) {
+	tree := NewCharlie(8)
+	tree.Store(0x0102)
+	tree.Store(0x0103)
+	tree.Store(0x0201)
+
+	var bb bytes.Buffer
+	if err := tree.WriteDOT(&bb, DOTOptions{KeyPrefixBits: 48, MaxDepth: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `digraph trie {
+	node [shape=circle, label=""];
+	n0;
+	n1 [style=dashed];
+	n0 -> n1 [label="1"];
+	n2 [style=dashed];
+	n0 -> n2 [label="2"];
+}
+`
+	if got := bb.String(); got != want {
+		t.Errorf("\nGOT:\n%s\nWANT:\n%s", got, want)
+	}
+}
@@ -0,0 +1,233 @@
+package goradix
+
+// Adaptive radix existence data structure
+
+// enode is a node of an Echo. It grows from 4 to 16, 48, and then 256 child
+// slots as children are added, and shrinks again as they are removed. The
+// number of child slots tells which of its layouts the node has:
+//
+//   - With 4 or 16 slots, keys holds the byte of the key for each child, in
+//     ascending order, and children holds the child for each of those bytes.
+//   - With 48 slots, keys holds one more than the index in children of the
+//     child for each of the 256 bytes, or 0 when the byte has no child.
+//   - With 256 slots, children holds the child for each byte, and keys is
+//     not used.
+type enode struct {
+	keys     []byte
+	children []*enode
+	count    int // number of children
+}
+
+// echoSizes are the numbers of child slots a node may have.
+var echoSizes = [...]int{4, 16, 48, 256}
+
+// echoLeaf is the child for the final byte of every key, so that the final
+// byte of a key costs only a child slot rather than a node.
+var echoLeaf = new(enode)
+
+func newENode(size int) *enode {
+	n := &enode{children: make([]*enode, size)}
+	switch size {
+	case 4, 16:
+		n.keys = make([]byte, size)
+	case 48:
+		n.keys = make([]byte, 256)
+	}
+	return n
+}
+
+// child returns the child of the node for the specified byte, or nil when it
+// has none.
+func (n *enode) child(b byte) *enode {
+	switch len(n.children) {
+	case 256:
+		return n.children[b]
+	case 48:
+		if i := n.keys[b]; i != 0 {
+			return n.children[i-1]
+		}
+		return nil
+	default:
+		for i := 0; i < n.count && n.keys[i] <= b; i++ {
+			if n.keys[i] == b {
+				return n.children[i]
+			}
+		}
+		return nil
+	}
+}
+
+// each invokes fn with the byte and child of each child of the node, in
+// ascending order of their bytes.
+func (n *enode) each(fn func(byte, *enode)) {
+	switch len(n.children) {
+	case 256:
+		for b, child := range n.children {
+			if child != nil {
+				fn(byte(b), child)
+			}
+		}
+	case 48:
+		for b, i := range n.keys {
+			if i != 0 {
+				fn(byte(b), n.children[i-1])
+			}
+		}
+	default:
+		for i := 0; i < n.count; i++ {
+			fn(n.keys[i], n.children[i])
+		}
+	}
+}
+
+// add adds the child of the node for the specified byte, which must not
+// already have one, growing the node when all of its slots are full.
+func (n *enode) add(b byte, child *enode) {
+	if n.count == len(n.children) {
+		for _, size := range echoSizes {
+			if size > n.count {
+				n.resize(size)
+				break
+			}
+		}
+	}
+
+	switch len(n.children) {
+	case 256:
+		n.children[b] = child
+	case 48:
+		i := 0
+		for n.children[i] != nil {
+			i++
+		}
+		n.children[i] = child
+		n.keys[b] = byte(i + 1)
+	default:
+		i := 0
+		for i < n.count && n.keys[i] < b {
+			i++
+		}
+		copy(n.keys[i+1:n.count+1], n.keys[i:n.count])
+		copy(n.children[i+1:n.count+1], n.children[i:n.count])
+		n.keys[i], n.children[i] = b, child
+	}
+	n.count++
+}
+
+// remove removes the child of the node for the specified byte, which must have
+// one, shrinking the node when no more than three quarters of the slots of the
+// next smaller size would be used. Waiting until then keeps a node from
+// changing size with every Store and Delete at the boundary between sizes.
+func (n *enode) remove(b byte) {
+	switch len(n.children) {
+	case 256:
+		n.children[b] = nil
+	case 48:
+		n.children[n.keys[b]-1] = nil
+		n.keys[b] = 0
+	default:
+		i := 0
+		for n.keys[i] != b {
+			i++
+		}
+		copy(n.keys[i:], n.keys[i+1:n.count])
+		copy(n.children[i:], n.children[i+1:n.count])
+		n.children[n.count-1] = nil
+	}
+	n.count--
+
+	for i := len(echoSizes) - 1; i > 0; i-- {
+		if len(n.children) == echoSizes[i] && n.count < echoSizes[i-1]*3/4 {
+			n.resize(echoSizes[i-1])
+			break
+		}
+	}
+}
+
+// resize changes the number of child slots of the node, which must be no fewer
+// than the number of its children.
+func (n *enode) resize(size int) {
+	resized := newENode(size)
+	n.each(func(b byte, child *enode) {
+		resized.add(b, child)
+	})
+	n.keys, n.children = resized.keys, resized.children
+}
+
+// Echo is a variant of Charlie with 8-bit digits, whose nodes only have as many
+// child slots as they need, like those of an adaptive radix tree. Each node
+// starts with 4 slots, and grows to 16, 48, and then 256 slots as children are
+// added, so a node with a single child takes about 100 bytes rather than the
+// 2 KiB of a node of Charlie. Load still visits no more than 8 nodes.
+type Echo struct {
+	root        *enode
+	keys, nodes uint64
+}
+
+// NewEcho returns a new, empty Echo.
+func NewEcho() *Echo {
+	return &Echo{root: newENode(echoSizes[0]), nodes: 1}
+}
+
+// Len returns the number of keys in the tree.
+func (tree *Echo) Len() uint64 {
+	return tree.keys
+}
+
+// Nodes returns the number of nodes allocated by the tree, including its root
+// node.
+func (tree *Echo) Nodes() uint64 {
+	return tree.nodes
+}
+
+// Delete removes the specified 64-bit key. Nodes left without children are
+// removed as well.
+func (tree *Echo) Delete(key uint64) {
+	var path [8]*enode
+	n := tree.root
+	for level := 0; level < 8; level++ {
+		path[level] = n
+		if n = n.child(byte(key >> uint(56-8*level))); n == nil {
+			return // key not present
+		}
+	}
+	tree.keys--
+
+	for level := 7; level >= 0; level-- {
+		path[level].remove(byte(key >> uint(56-8*level)))
+		if level == 0 || path[level].count > 0 {
+			break
+		}
+		tree.nodes-- // the root node is never removed
+	}
+}
+
+// Load returns whether or not the specified 64-bit key is present.
+func (tree *Echo) Load(key uint64) bool {
+	n := tree.root
+	for shift := 56; shift >= 0; shift -= 8 {
+		if n = n.child(byte(key >> uint(shift))); n == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Store stores the existence of the specified 64-bit key.
+func (tree *Echo) Store(key uint64) {
+	n := tree.root
+	for shift := 56; shift > 0; shift -= 8 {
+		b := byte(key >> uint(shift))
+		child := n.child(b)
+		if child == nil {
+			child = newENode(echoSizes[0])
+			tree.nodes++
+			n.add(b, child)
+		}
+		n = child
+	}
+	if b := byte(key); n.child(b) == nil {
+		n.add(b, echoLeaf)
+		tree.keys++
+	}
+}
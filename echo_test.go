@@ -0,0 +1,152 @@
+package goradix
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// countNodes returns the number of nodes below and including the node, and
+// checks that each has as many children as it counts.
+func (n *enode) countNodes(t *testing.T) uint64 {
+	if n == echoLeaf {
+		return 0
+	}
+	var children int
+	nodes := uint64(1)
+	n.each(func(_ byte, child *enode) {
+		children++
+		nodes += child.countNodes(t)
+	})
+	if children != n.count {
+		t.Errorf("GOT: %v; WANT: %v", children, n.count)
+	}
+	return nodes
+}
+
+func TestEchoStore(t *testing.T) {
+	tree := NewEcho()
+
+	if got, want := tree.Load(0), false; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	tree.Store(8)
+	tree.Store(0xFFFE)
+	tree.Store(8)
+
+	if got, want := tree.Load(9), false; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := tree.Load(8), true; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := tree.Load(0xFFFF), false; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := tree.Load(0xFFFE), true; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := tree.Len(), uint64(2); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	// The keys share their first six bytes.
+	if got, want := tree.Nodes(), uint64(9); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestEchoDelete(t *testing.T) {
+	tree := NewEcho()
+
+	tree.Store(8)
+	tree.Delete(9)
+	tree.Delete(1 << 63)
+	if got, want := tree.Load(8), true; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	tree.Delete(8)
+	if got, want := tree.Load(8), false; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := tree.Len(), uint64(0); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := tree.Nodes(), uint64(1); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestEchoGrowAndShrink(t *testing.T) {
+	tree := NewEcho()
+	sizes := func() int { return len(tree.root.children) }
+
+	// Every key differs in its first byte, so each is a child of the root.
+	key := func(b int) uint64 { return uint64(b)<<56 | uint64(b) }
+	want := []struct{ count, size int }{{4, 4}, {5, 16}, {16, 16}, {17, 48}, {48, 48}, {49, 256}, {256, 256}}
+	var b int
+	for _, w := range want {
+		for ; b < w.count; b++ {
+			tree.Store(key(b))
+		}
+		if got := sizes(); got != w.size {
+			t.Errorf("children: %d; GOT: %v; WANT: %v", w.count, got, w.size)
+		}
+	}
+	for b := 0; b < 256; b++ {
+		if got, want := tree.Load(key(b)), true; got != want {
+			t.Errorf("key: %#x; GOT: %v; WANT: %v", key(b), got, want)
+		}
+	}
+
+	want = []struct{ count, size int }{{36, 256}, {35, 48}, {12, 48}, {11, 16}, {3, 16}, {2, 4}}
+	b = 256
+	for _, w := range want {
+		for ; b > w.count; b-- {
+			tree.Delete(key(b - 1))
+		}
+		if got := sizes(); got != w.size {
+			t.Errorf("children: %d; GOT: %v; WANT: %v", w.count, got, w.size)
+		}
+		for i := 0; i < 256; i++ {
+			if got, want := tree.Load(key(i)), i < b; got != want {
+				t.Errorf("key: %#x; GOT: %v; WANT: %v", key(i), got, want)
+			}
+		}
+	}
+}
+
+func TestEchoRandom(t *testing.T) {
+	tree := NewEcho()
+	oracle := make(map[uint64]struct{})
+	r := rand.New(rand.NewSource(1))
+
+	// Few distinct bytes in most places, so that nodes fill and empty.
+	key := func() uint64 { return r.Uint64() & 0x0300000000003FFF }
+
+	for i := 0; i < 20000; i++ {
+		k := key()
+		if r.Intn(3) == 0 {
+			tree.Delete(k)
+			delete(oracle, k)
+		} else {
+			tree.Store(k)
+			oracle[k] = struct{}{}
+		}
+		k = key()
+		if _, want := oracle[k]; tree.Load(k) != want {
+			t.Fatalf("key: %#x; GOT: %v; WANT: %v", k, !want, want)
+		}
+	}
+	if got, want := tree.Len(), uint64(len(oracle)); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := tree.root.countNodes(t), tree.Nodes(); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	for k := range oracle {
+		if !tree.Load(k) {
+			t.Errorf("key: %#x; GOT: %v; WANT: %v", k, false, true)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package goradix
+
+import (
+	"strings"
+	"unicode"
+)
+
+// foldedValue is stored in place of the client's value by a folding Alpha, so
+// that the key as the client spelled it may be returned when iterating.
+type foldedValue struct {
+	key   string
+	value interface{}
+}
+
+// NewFoldingAlpha returns a new Trie that applies the specified fold function
+// to every key before it is used to store, load, or delete a value, and to every
+// prefix given to Keys. Keys that fold to the same string refer to the same
+// value. The most recently stored spelling of each key is retained and returned
+// by Keys.
+//
+// The fold function must be deterministic, and folding a string that has
+// already been folded must not change it. When it does not preserve prefixes,
+// i.e., fold(a+b) does not begin with fold(a), Keys may not find every key with
+// a given prefix.
+//
+// FoldCase may be used for case insensitive keys. For Unicode normalized keys,
+// compose a normalization form with FoldCase, e.g., using the
+// golang.org/x/text/unicode/norm package:
+//
+//	trie := goradix.NewFoldingAlpha(func(s string) string {
+//	    return goradix.FoldCase(norm.NFKC.String(s))
+//	})
+func NewFoldingAlpha(fold func(string) string) *Alpha {
+	return &Alpha{value: &alphaHeader{fold: fold}}
+}
+
+// FoldCase returns s with simple Unicode case folding applied to each rune, so
+// that two strings for which strings.EqualFold returns true fold to the same
+// string. Each rune is mapped to one member of the set of runes that are equal
+// to it under simple case folding: the lower case letter for an ASCII letter,
+// and the smallest member of the set otherwise. When s has nothing to fold, it
+// is returned without allocation.
+func FoldCase(s string) string {
+	return strings.Map(foldRune, s)
+}
+
+func foldRune(r rune) rune {
+	if r < 0x80 {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}
+	// unicode.SimpleFold steps through the runes that are equal under simple
+	// case folding, in ascending order, and wraps around back to r. A set
+	// that holds an ASCII letter holds no smaller rune than its upper case.
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	if 'A' <= min && min <= 'Z' {
+		return min + 'a' - 'A'
+	}
+	return min
+}
@@ -0,0 +1,135 @@
+package goradix
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestFoldCase(t *testing.T) {
+	cases := []struct {
+		input, want string
+	}{
+		{"", ""},
+		{"already-folded", "already-folded"},
+		{"Hello, World!", "hello, world!"},
+		{"ſ", "s"}, // long s
+		{"K", "k"}, // Kelvin sign
+		{"σίσυφος", "ΣΊΣΥΦΟΣ"},
+		{"Straße", "straße"},
+		{"\u1fd3", "\u0390"}, // Greek iota with dialytika and tonos
+		{"\u1fe3", "\u03b0"}, // Greek upsilon with dialytika and tonos
+		{"\ufb06", "\ufb05"}, // ligatures of long s and of s with t
+	}
+
+	for _, item := range cases {
+		if got, want := FoldCase(item.input), item.want; got != want {
+			t.Errorf("%q; GOT: %q; WANT: %q", item.input, got, want)
+		}
+	}
+}
+
+func TestFoldCaseEqualFold(t *testing.T) {
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		folded := FoldCase(string(r))
+		if got := FoldCase(folded); got != folded {
+			t.Errorf("%U: GOT: %q; WANT: %q", r, got, folded)
+		}
+		if !strings.EqualFold(folded, string(r)) {
+			t.Errorf("%U: GOT: %q; WANT: a string equal to %q under case folding", r, folded, string(r))
+		}
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if got := FoldCase(string(f)); got != folded {
+				t.Errorf("%U, %U: GOT: %q; WANT: %q", r, f, got, folded)
+			}
+		}
+	}
+}
+
+func TestFoldingAlphaLoad(t *testing.T) {
+	root := NewFoldingAlpha(FoldCase)
+	root.Store("Sam", 1)
+	root.Store("Samuel", 2)
+
+	t.Run("SameSpelling", func(t *testing.T) {
+		value, ok := root.Load("Sam")
+		if got, want := ok, true; got != want {
+			t.Fatalf("GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := value, 1; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	})
+
+	t.Run("DifferentSpelling", func(t *testing.T) {
+		value, ok := root.Load("sAMUEL")
+		if got, want := ok, true; got != want {
+			t.Fatalf("GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := value, 2; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	})
+
+	t.Run("MissingKey", func(t *testing.T) {
+		_, ok := root.Load("samantha")
+		if got, want := ok, false; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	})
+}
+
+func TestFoldingAlphaStoreReplacesSpelling(t *testing.T) {
+	root := NewFoldingAlpha(FoldCase)
+	root.Store("Robert", 1)
+	root.Store("ROBERT", 2)
+
+	keys := root.Keys("", 0)
+	if got, want := len(keys), 1; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := keys[0], "ROBERT"; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	value, ok := root.Load("robert")
+	if got, want := ok, true; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := value, 2; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestFoldingAlphaDelete(t *testing.T) {
+	root := NewFoldingAlpha(FoldCase)
+	root.Store("Roberta", 1)
+	root.Store("Roberto", 2)
+
+	root.Delete("ROBERTA")
+
+	if _, ok := root.Load("roberta"); ok {
+		t.Errorf("GOT: %v; WANT: %v", ok, false)
+	}
+	if _, ok := root.Load("roberto"); !ok {
+		t.Errorf("GOT: %v; WANT: %v", ok, true)
+	}
+}
+
+func TestFoldingAlphaKeys(t *testing.T) {
+	root := NewFoldingAlpha(FoldCase)
+	root.Store("/Users/Sam", 1)
+	root.Store("/users/samuel", 2)
+	root.Store("/USERS/Robert", 3)
+
+	keys := root.Keys("/users/SAM", 0)
+	if got, want := len(keys), 2; got != want {
+		t.Fatalf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := keys[0], "/Users/Sam"; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := keys[1], "/users/samuel"; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
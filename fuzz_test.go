@@ -0,0 +1,214 @@
+package goradix
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// Each fuzz input is decoded into a sequence of operations. Every operation
+// starts with a byte whose low two bits select the operation, and whose
+// remaining bits give the length of the key bytes that follow it. The checked-in
+// seed corpus lives in testdata/fuzz, where each file is named after the edge
+// case it exercises.
+const (
+	fuzzStore = iota
+	fuzzDelete
+	fuzzLoad
+	fuzzKeys
+)
+
+// fuzzOps decodes data into a sequence of operations, and invokes fn with each
+// one, along with the key bytes that follow it, and, for fuzzKeys, the byte
+// after them. Operations are decoded until data runs out.
+func fuzzOps(data []byte, maxKeyLength int, fn func(op int, key []byte, extra byte)) {
+	for len(data) > 0 {
+		op, n := int(data[0]&3), int(data[0]>>2)%(maxKeyLength+1)
+		data = data[1:]
+		if n > len(data) {
+			n = len(data)
+		}
+		key := data[:n]
+		data = data[n:]
+		var extra byte
+		if op == fuzzKeys && len(data) > 0 {
+			extra = data[0]
+			data = data[1:]
+		}
+		fn(op, key, extra)
+	}
+}
+
+// fuzzUint64 returns the key bytes as a big-endian number, so that short keys
+// share their most significant bits with one another.
+func fuzzUint64(key []byte) uint64 {
+	var u uint64
+	for _, b := range key {
+		u = u<<8 | uint64(b)
+	}
+	return u
+}
+
+func FuzzAlpha(f *testing.F) {
+	f.Add([]byte("\x0csam\x18samuel\x0dsam\x1asamuel\x03"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		trie := new(Alpha)
+		oracle := make(map[string]interface{})
+		var step int
+
+		fuzzOps(data, 63, func(op int, b []byte, extra byte) {
+			step++
+			key := string(b)
+			switch op {
+			case fuzzStore:
+				trie.Store(key, step)
+				oracle[key] = step
+			case fuzzDelete:
+				trie.Delete(key)
+				delete(oracle, key)
+			case fuzzLoad:
+				value, ok := trie.Load(key)
+				want, wantOK := oracle[key]
+				if value != want || ok != wantOK {
+					t.Fatalf("step %d: Load(%q): GOT: %v, %v; WANT: %v, %v", step, key, value, ok, want, wantOK)
+				}
+			case fuzzKeys:
+				limit := int(extra % 4)
+				var want []string
+				for k := range oracle {
+					if strings.HasPrefix(k, key) {
+						want = append(want, k)
+					}
+				}
+				sort.Strings(want)
+				if limit > 0 && len(want) > limit {
+					want = want[:limit]
+				}
+				if got := trie.Keys(key, limit); !reflect.DeepEqual(got, want) {
+					t.Fatalf("step %d: Keys(%q, %d):\nGOT:  %q\nWANT: %q", step, key, limit, got, want)
+				}
+			}
+
+			if err := trie.Validate(); err != nil {
+				t.Fatalf("step %d: %s\n%s", step, err, trie.Bytes())
+			}
+			if got := alphaContents(trie); !reflect.DeepEqual(got, oracle) {
+				t.Fatalf("step %d:\nGOT:  %v\nWANT: %v\n%s", step, got, oracle, trie.Bytes())
+			}
+		})
+	})
+}
+
+// existenceTrie is the part of a uint64 existence trie that fuzzExistence
+// checks against its oracle.
+type existenceTrie interface {
+	Store(uint64)
+	Delete(uint64)
+	Load(uint64) bool
+	Len() uint64
+}
+
+// fuzzExistence checks a uint64 existence trie against a map oracle. When the
+// trie can list its keys in order, a fuzzKeys operation lists up to a few keys
+// from its key, and every step compares all of the keys of the trie with those
+// of the oracle. Otherwise, fuzzKeys is a Load.
+func fuzzExistence(t *testing.T, data []byte, trie existenceTrie) {
+	ranger, _ := trie.(interface {
+		RangeFrom(uint64, func(uint64) bool)
+	})
+	oracle := make(map[uint64]struct{})
+	var step int
+
+	// keysFrom returns the keys of the oracle that are not less than start,
+	// in ascending order, up to limit keys when limit is positive.
+	keysFrom := func(start uint64, limit int) []uint64 {
+		var keys []uint64
+		for key := range oracle {
+			if key >= start {
+				keys = append(keys, key)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+		if limit > 0 && len(keys) > limit {
+			keys = keys[:limit]
+		}
+		return keys
+	}
+
+	// rangeFrom returns the keys of the trie that RangeFrom gives from start,
+	// up to limit keys when limit is positive.
+	rangeFrom := func(start uint64, limit int) []uint64 {
+		var keys []uint64
+		ranger.RangeFrom(start, func(key uint64) bool {
+			keys = append(keys, key)
+			return limit <= 0 || len(keys) < limit
+		})
+		return keys
+	}
+
+	fuzzOps(data, 8, func(op int, b []byte, extra byte) {
+		step++
+		key := fuzzUint64(b)
+		switch {
+		case op == fuzzStore:
+			trie.Store(key)
+			oracle[key] = struct{}{}
+		case op == fuzzDelete:
+			trie.Delete(key)
+			delete(oracle, key)
+		case op == fuzzKeys && ranger != nil:
+			limit := int(extra % 4)
+			if got, want := rangeFrom(key, limit), keysFrom(key, limit); !reflect.DeepEqual(got, want) {
+				t.Fatalf("step %d: RangeFrom(%#x), %d keys:\nGOT:  %#x\nWANT: %#x", step, key, limit, got, want)
+			}
+		default:
+			_, want := oracle[key]
+			if got := trie.Load(key); got != want {
+				t.Fatalf("step %d: Load(%#x): GOT: %v; WANT: %v", step, key, got, want)
+			}
+		}
+
+		if got, want := trie.Len(), uint64(len(oracle)); got != want {
+			t.Fatalf("step %d: Len: GOT: %v; WANT: %v", step, got, want)
+		}
+		if ranger != nil {
+			if got, want := rangeFrom(0, 0), keysFrom(0, 0); !reflect.DeepEqual(got, want) {
+				t.Fatalf("step %d:\nGOT:  %#x\nWANT: %#x", step, got, want)
+			}
+			return
+		}
+		for key := range oracle {
+			if !trie.Load(key) {
+				t.Fatalf("step %d: Load(%#x): GOT: %v; WANT: %v", step, key, false, true)
+			}
+		}
+	})
+}
+
+func FuzzBravo(f *testing.F) {
+	f.Add([]byte("\x04\x08\x08\xff\xfe\x06\x09\x05\x08\x06\x08"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, dst := range []*Bravo{NewBravo(), NewBitmapBravo()} {
+			fuzzExistence(t, data, dst)
+		}
+	})
+}
+
+func FuzzCharlie(f *testing.F) {
+	f.Add(uint8(4), []byte("\x04\x08\x08\xff\xfe\x06\x09\x05\x08\x06\x08"))
+	f.Fuzz(func(t *testing.T, bits uint8, data []byte) {
+		// Wider nodes allocate too many children to fuzz quickly.
+		for _, tree := range []*Charlie{NewCharlie(bits % 16), NewBitmapCharlie(bits % 16), NewSparseCharlie(bits % 16)} {
+			fuzzExistence(t, data, tree)
+		}
+	})
+}
+
+func FuzzEcho(f *testing.F) {
+	f.Add([]byte("\x04\x08\x08\xff\xfe\x06\x09\x05\x08\x06\x08"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tree := NewEcho()
+		fuzzExistence(t, data, tree)
+	})
+}
@@ -1,3 +1,3 @@
 module github.com/karrick/goradix
 
-go 1.12
+go 1.18
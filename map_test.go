@@ -0,0 +1,243 @@
+package goradix
+
+import (
+	"runtime"
+	"testing"
+)
+
+// uint64Map is implemented by both BravoMap and CharlieMap.
+type uint64Map interface {
+	Store(uint64, interface{})
+	Load(uint64) (interface{}, bool)
+	Delete(uint64)
+	Len() uint64
+}
+
+func uint64Maps() map[string]uint64Map {
+	bravoPruning := NewBravoMap()
+	bravoPruning.SetPruneOnDelete(true)
+	charliePruning := NewCharlieMap(4)
+	charliePruning.SetPruneOnDelete(true)
+	return map[string]uint64Map{
+		"bravo":           NewBravoMap(),
+		"bravo-prune":     bravoPruning,
+		"charlie-1":       NewCharlieMap(1),
+		"charlie-2":       NewCharlieMap(2),
+		"charlie-4":       NewCharlieMap(4),
+		"charlie-4-prune": charliePruning,
+		"charlie-8":       NewCharlieMap(8),
+	}
+}
+
+func TestUint64Map(t *testing.T) {
+	for name, m := range uint64Maps() {
+		if _, ok := m.Load(0); ok {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, ok, false)
+		}
+
+		m.Store(8, "eight")
+		m.Store(0xFFFE, nil)
+		m.Store(8, "EIGHT")
+
+		if value, ok := m.Load(8); !ok || value != "EIGHT" {
+			t.Errorf("%s: GOT: %v, %v; WANT: %v, %v", name, value, ok, "EIGHT", true)
+		}
+		if value, ok := m.Load(0xFFFE); !ok || value != nil {
+			t.Errorf("%s: GOT: %v, %v; WANT: %v, %v", name, value, ok, nil, true)
+		}
+		if _, ok := m.Load(9); ok {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, ok, false)
+		}
+		if got, want := m.Len(), uint64(2); got != want {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, got, want)
+		}
+
+		m.Delete(8)
+		m.Delete(8)
+		m.Delete(9)
+		if _, ok := m.Load(8); ok {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, ok, false)
+		}
+		if got, want := m.Len(), uint64(1); got != want {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, got, want)
+		}
+	}
+}
+
+func TestUint64MapValues(t *testing.T) {
+	keys := testKeys(300)
+	for name, m := range uint64Maps() {
+		values := make(map[uint64]interface{})
+		for i, key := range keys {
+			m.Store(key, i)
+			values[key] = i
+		}
+
+		// Replace the values of the first hundred keys, delete the next
+		// hundred, and store some of those again with nil values.
+		for i, key := range keys[:100] {
+			m.Store(key, -i)
+			values[key] = -i
+		}
+		for _, key := range keys[100:200] {
+			m.Delete(key)
+			delete(values, key)
+		}
+		for _, key := range keys[150:200] {
+			m.Store(key, nil)
+			values[key] = nil
+		}
+
+		if got, want := m.Len(), uint64(len(values)); got != want {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, got, want)
+		}
+		for _, key := range keys {
+			value, ok := m.Load(key)
+			wantValue, wantOK := values[key]
+			if ok != wantOK || value != wantValue {
+				t.Errorf("%s: Load(%#x): GOT: %v, %v; WANT: %v, %v", name, key, value, ok, wantValue, wantOK)
+			}
+		}
+	}
+}
+
+// allocSink keeps the nodes allocated by allocated on the heap.
+var allocSink interface{}
+
+// allocated returns the number of bytes of heap allocated while running fn.
+func allocated(fn func()) uint64 {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	fn()
+	runtime.ReadMemStats(&after)
+	return after.TotalAlloc - before.TotalAlloc
+}
+
+func TestMapNodesAboveLeavesHoldNoValues(t *testing.T) {
+	bravoInner := allocated(func() { allocSink = new(bmnode) })
+	bravoLeaf := allocated(func() { allocSink = &bmnode{leaf: new(interface{})} })
+	charlieInner := allocated(func() { allocSink = &cmnode{children: make([]*cmnode, 16)} })
+	charlieLeaf := allocated(func() { allocSink = &cmnode{leaf: new(interface{})} })
+
+	tries := []struct {
+		name        string
+		m           uint64Map
+		inner, leaf uint64 // bytes allocated for each node
+		depth       uint64 // nodes from the root node to a leaf
+		branch      uint64 // nodes above the leaf below bit 12 of a key
+	}{
+		{"bravo", NewBravoMap(), bravoInner, bravoLeaf, 64, 12},
+		{"charlie-4", NewCharlieMap(4), charlieInner, charlieLeaf, 16, 3},
+	}
+	for _, trie := range tries {
+		// Each step allocates the nodes of the key it stores below those
+		// already in the map, of which only the leaf has room for a value.
+		steps := []struct {
+			name        string
+			fn          func()
+			inner, leaf uint64 // nodes allocated
+		}{
+			{"first key", func() { trie.m.Store(0x100, "a") }, trie.depth - 1, 1},
+			{"sibling leaf", func() { trie.m.Store(0x101, "b") }, 0, 1},
+			{"replaced value", func() { trie.m.Store(0x101, "c") }, 0, 0},
+			{"deleted key", func() { trie.m.Delete(0x101) }, 0, 0},
+			{"restored key", func() { trie.m.Store(0x101, "d") }, 0, 1},
+			{"new branch", func() { trie.m.Store(0x1000, "e") }, trie.branch, 1},
+		}
+		for _, step := range steps {
+			got := allocated(step.fn)
+			if want := step.inner*trie.inner + step.leaf*trie.leaf; got != want {
+				t.Errorf("%s: %s: GOT: %v bytes; WANT: %v bytes for %v nodes above leaves and %v leaves", trie.name, step.name, got, want, step.inner, step.leaf)
+			}
+		}
+		if value, ok := trie.m.Load(0x101); !ok || value != "d" {
+			t.Errorf("%s: GOT: %v, %v; WANT: %v, %v", trie.name, value, ok, "d", true)
+		}
+	}
+}
+
+func TestBravoMapCompact(t *testing.T) {
+	for _, prune := range []bool{false, true} {
+		bm := NewBravoMap()
+		bm.SetPruneOnDelete(prune)
+
+		bm.Store(0, "zero")
+		bm.Store(1, "one")
+		bm.Store(0x80, "eighty")
+		if got, want := bm.Nodes(), uint64(74); got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+
+		// Without pruning, the branch to 0x80 is left behind for Compact.
+		bm.Delete(0x80)
+		wantNodes, wantFreed := uint64(73), uint64(7)
+		if prune {
+			wantNodes, wantFreed = 66, 0
+		}
+		if got, want := bm.Nodes(), wantNodes; got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+		if got, want := bm.Compact(), wantFreed; got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+		if got, want := bm.Nodes(), uint64(66); got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+		if value, ok := bm.Load(1); !ok || value != "one" {
+			t.Errorf("prune: %v; GOT: %v, %v; WANT: %v, %v", prune, value, ok, "one", true)
+		}
+
+		bm.Delete(1)
+		bm.Delete(0)
+		bm.Compact()
+		if got, want := bm.Nodes(), uint64(1); got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+		if got, want := bm.Len(), uint64(0); got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+	}
+}
+
+func TestCharlieMapCompact(t *testing.T) {
+	for _, prune := range []bool{false, true} {
+		cm := NewCharlieMap(4)
+		cm.SetPruneOnDelete(prune)
+
+		cm.Store(0, "zero")
+		cm.Store(1, "one")
+		cm.Store(0x80, "eighty")
+		if got, want := cm.Nodes(), uint64(20); got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+
+		// Without pruning, the branch to 0x80 is left behind for Compact.
+		cm.Delete(0x80)
+		wantNodes, wantFreed := uint64(19), uint64(1)
+		if prune {
+			wantNodes, wantFreed = 18, 0
+		}
+		if got, want := cm.Nodes(), wantNodes; got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+		if got, want := cm.Compact(), wantFreed; got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+		if got, want := cm.Nodes(), uint64(18); got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+		if value, ok := cm.Load(1); !ok || value != "one" {
+			t.Errorf("prune: %v; GOT: %v, %v; WANT: %v, %v", prune, value, ok, "one", true)
+		}
+
+		cm.Delete(1)
+		cm.Delete(0)
+		cm.Compact()
+		if got, want := cm.Nodes(), uint64(1); got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+		if got, want := cm.Len(), uint64(0); got != want {
+			t.Errorf("prune: %v; GOT: %v; WANT: %v", prune, got, want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package goradix
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Op identifies an operation on a Trie.
+type Op int
+
+const (
+	// OpStore is the Store operation.
+	OpStore Op = iota
+
+	// OpLoad is the Load operation.
+	OpLoad
+
+	// OpDelete is the Delete operation.
+	OpDelete
+
+	opCount // number of operations
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpStore:
+		return "store"
+	case OpLoad:
+		return "load"
+	case OpDelete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// Observation describes one completed operation on a Trie.
+type Observation struct {
+	// Op is the operation.
+	Op Op
+
+	// Hit is true when the key was in the Trie before the operation.
+	Hit bool
+
+	// Nodes is the number of nodes the operation visited, including the root
+	// node.
+	Nodes int
+
+	// Split is true when a Store split a node of an Alpha.
+	Split bool
+
+	// Merge is true when a Delete merged a node of an Alpha with its only
+	// child.
+	Merge bool
+
+	// Duration is how long the operation took.
+	Duration time.Duration
+}
+
+// Observer receives an Observation after each Store, Load, and Delete
+// operation, for example to export metrics. It is invoked by the goroutine
+// that performed the operation.
+type Observer interface {
+	Observe(o Observation)
+}
+
+// observe completes the observation of an operation that began at start, and
+// sends it to observer.
+func observe(observer Observer, start time.Time, o *Observation) {
+	o.Duration = time.Since(start)
+	observer.Observe(*o)
+}
+
+// OpCounts holds the totals of the observations of one operation.
+type OpCounts struct {
+	Calls    uint64
+	Hits     uint64
+	Nodes    uint64
+	Splits   uint64
+	Merges   uint64
+	Duration time.Duration
+}
+
+// Misses returns the number of calls that did not find their key.
+func (c OpCounts) Misses() uint64 {
+	return c.Calls - c.Hits
+}
+
+// CountingObserver is an Observer that totals its observations for each
+// operation. It is safe to use from multiple goroutines, and its zero value is
+// ready to use.
+type CountingObserver struct {
+	counts [opCount]struct {
+		calls, hits, nodes, splits, merges, duration uint64
+	}
+}
+
+// Observe adds the observation to the totals for its operation.
+func (co *CountingObserver) Observe(o Observation) {
+	if o.Op < 0 || o.Op >= opCount {
+		return
+	}
+	c := &co.counts[o.Op]
+	atomic.AddUint64(&c.calls, 1)
+	if o.Hit {
+		atomic.AddUint64(&c.hits, 1)
+	}
+	atomic.AddUint64(&c.nodes, uint64(o.Nodes))
+	if o.Split {
+		atomic.AddUint64(&c.splits, 1)
+	}
+	if o.Merge {
+		atomic.AddUint64(&c.merges, 1)
+	}
+	atomic.AddUint64(&c.duration, uint64(o.Duration))
+}
+
+// Counts returns the totals of the observations of the specified operation.
+func (co *CountingObserver) Counts(op Op) OpCounts {
+	if op < 0 || op >= opCount {
+		return OpCounts{}
+	}
+	c := &co.counts[op]
+	return OpCounts{
+		Calls:    atomic.LoadUint64(&c.calls),
+		Hits:     atomic.LoadUint64(&c.hits),
+		Nodes:    atomic.LoadUint64(&c.nodes),
+		Splits:   atomic.LoadUint64(&c.splits),
+		Merges:   atomic.LoadUint64(&c.merges),
+		Duration: time.Duration(atomic.LoadUint64(&c.duration)),
+	}
+}
+
+// SetObserver arranges for the observer to receive an Observation after each
+// Store, Load, and Delete operation on the Trie. When observer is nil, the
+// operations are no longer observed.
+func (tn *Alpha) SetObserver(observer Observer) {
+	tn.ensureHeader().observer = observer
+}
+
+// observer returns the Observer of the Trie, or nil when it has none.
+func (tn *Alpha) observer() Observer {
+	if h := tn.header(); h != nil {
+		return h.observer
+	}
+	return nil
+}
+
+// SetObserver arranges for the observer to receive an Observation after each
+// Store, Load, and Delete operation on the DST. When observer is nil, the
+// operations are no longer observed.
+func (dst *Bravo) SetObserver(observer Observer) {
+	dst.observer = observer
+}
+
+// SetObserver arranges for the observer to receive an Observation after each
+// Store, Load, and Delete operation on the tree. When observer is nil, the
+// operations are no longer observed.
+func (tree *Charlie) SetObserver(observer Observer) {
+	tree.observer = observer
+}
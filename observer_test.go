@@ -0,0 +1,149 @@
+package goradix
+
+import (
+	"reflect"
+	"testing"
+)
+
+// observationRecorder is an Observer that records each observation, without
+// its duration.
+type observationRecorder []Observation
+
+func (or *observationRecorder) Observe(o Observation) {
+	o.Duration = 0
+	*or = append(*or, o)
+}
+
+func TestAlphaObserver(t *testing.T) {
+	trie := new(Alpha)
+	var got observationRecorder
+	trie.SetObserver(&got)
+
+	trie.Store("roberta", 1)
+	trie.Store("roberto", 2)
+	trie.Store("roberto", 3)
+	trie.Load("robert")
+	trie.Load("roberto")
+	trie.Delete("roberta")
+	trie.Delete("roberta")
+
+	want := observationRecorder{
+		{Op: OpStore, Nodes: 1},
+		{Op: OpStore, Nodes: 2, Split: true},
+		{Op: OpStore, Hit: true, Nodes: 3},
+		{Op: OpLoad, Nodes: 3},
+		{Op: OpLoad, Hit: true, Nodes: 3},
+		{Op: OpDelete, Hit: true, Nodes: 3, Merge: true},
+		{Op: OpDelete, Nodes: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nGOT:  %+v\nWANT: %+v", got, want)
+	}
+}
+
+func TestBravoObserver(t *testing.T) {
+	dst := NewBravo()
+	var got observationRecorder
+	dst.SetObserver(&got)
+
+	dst.Store(1)
+	dst.Store(1)
+	dst.Load(0)
+	dst.Delete(1)
+
+	want := observationRecorder{
+		{Op: OpStore, Nodes: 1},
+		{Op: OpStore, Hit: true, Nodes: 65},
+		{Op: OpLoad, Nodes: 64},
+		{Op: OpDelete, Hit: true, Nodes: 65},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nGOT:  %+v\nWANT: %+v", got, want)
+	}
+}
+
+func TestCharlieObserver(t *testing.T) {
+	tree := NewCharlie(4)
+	var got observationRecorder
+	tree.SetObserver(&got)
+
+	tree.Store(0x10)
+	tree.Store(0x10)
+	tree.Load(0x20)
+	tree.Delete(0x10)
+
+	want := observationRecorder{
+		{Op: OpStore, Nodes: 1},
+		{Op: OpStore, Hit: true, Nodes: 17},
+		{Op: OpLoad, Nodes: 15},
+		{Op: OpDelete, Hit: true, Nodes: 17},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nGOT:  %+v\nWANT: %+v", got, want)
+	}
+}
+
+func TestCountingObserver(t *testing.T) {
+	trie := new(Alpha)
+	var co CountingObserver
+	trie.SetObserver(&co)
+
+	trie.Store("sam", 1)
+	trie.Store("sally", 2)
+	trie.Load("sam")
+	trie.Load("samuel")
+	trie.Delete("sally")
+
+	if got, want := co.Counts(OpStore), (OpCounts{Calls: 2, Nodes: 3, Splits: 1, Duration: co.Counts(OpStore).Duration}); got != want {
+		t.Errorf("GOT: %+v; WANT: %+v", got, want)
+	}
+	load := co.Counts(OpLoad)
+	if got, want := load.Calls, uint64(2); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := load.Misses(), uint64(1); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := co.Counts(OpDelete).Merges, uint64(1); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestObserverOffDoesNotAllocate(t *testing.T) {
+	trie := new(Alpha)
+	trie.Store("sally", 1)
+	dst := NewBravo()
+	dst.Store(1)
+	tree := NewCharlie(4)
+	tree.Store(1)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		trie.Load("sally")
+		trie.Store("sally", 1)
+		trie.Delete("sam")
+		dst.Load(1)
+		dst.Store(1)
+		dst.Delete(2)
+		tree.Load(1)
+		tree.Store(1)
+		tree.Delete(2)
+	})
+	if allocs != 0 {
+		t.Errorf("GOT: %v; WANT: %v", allocs, 0)
+	}
+}
+
+func TestObserverOnDoesNotAllocate(t *testing.T) {
+	trie := new(Alpha)
+	trie.Store("sally", 1)
+	var co CountingObserver
+	trie.SetObserver(&co)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		trie.Load("sally")
+		trie.Store("sally", 1)
+	})
+	if allocs != 0 {
+		t.Errorf("GOT: %v; WANT: %v", allocs, 0)
+	}
+}
@@ -0,0 +1,234 @@
+package goradix
+
+// Branches left behind by Delete lead to no key, so when a descent ends on one,
+// these methods fall back on walking the trie, which backtracks out of them.
+
+// Min returns the smallest key in the DST, and false when the DST is empty.
+func (dst *Bravo) Min() (uint64, bool) {
+	return dst.extreme(false)
+}
+
+// Max returns the largest key in the DST, and false when the DST is empty.
+func (dst *Bravo) Max() (uint64, bool) {
+	return dst.extreme(true)
+}
+
+// Successor returns the smallest key in the DST that is not less than x, and
+// false when there is none.
+func (dst *Bravo) Successor(x uint64) (uint64, bool) {
+	return dst.neighbor(x, false)
+}
+
+// Predecessor returns the largest key in the DST that is not greater than x,
+// and false when there is none.
+func (dst *Bravo) Predecessor(x uint64) (uint64, bool) {
+	return dst.neighbor(x, true)
+}
+
+func (dst *Bravo) extreme(max bool) (uint64, bool) {
+	if dst.root == nil {
+		return 0, false
+	}
+	tail := dst.tail()
+	if key, ok := dst.root.extreme(0, initialMask, tail, max); ok {
+		return key, true
+	}
+	return dst.root.first(0, tail, false, max)
+}
+
+// neighbor returns the key nearest to x, on or after it when reverse is false,
+// and on or before it when reverse is true.
+func (dst *Bravo) neighbor(x uint64, reverse bool) (uint64, bool) {
+	if dst.root == nil {
+		return 0, false
+	}
+
+	// Follow the path of x, remembering the deepest branch that leaves it
+	// toward the side of x that is wanted.
+	node := dst.root
+	tail := dst.tail()
+	var alt *bnode
+	var altKey, altMask uint64
+	for mask := initialMask; mask > tail && node != nil; mask >>= 1 {
+		// The path of x and the wanted side of it, as a pair of
+		// children and their bits.
+		next, other, otherBit := node.left, node.right, mask
+		if x&mask != 0 {
+			next, other, otherBit = node.right, node.left, 0
+		}
+		if other != nil && (otherBit != 0) != reverse {
+			alt, altKey, altMask = other, x&^(mask<<1-1)|otherBit, mask>>1
+		}
+		node = next
+	}
+	if node != nil {
+		// The keys that end at node, on the wanted side of x.
+		lo, hi := x, ^uint64(0)
+		if reverse {
+			lo, hi = 0, x
+		}
+		base := x &^ tail
+		if key, ok := bitmapExtreme(node.leaves(tail)&bitmapRange(base, lo, hi), base, reverse); ok {
+			return key, true
+		}
+	}
+	if alt == nil {
+		return 0, false
+	}
+	if key, ok := alt.extreme(altKey, altMask, tail, reverse); ok {
+		return key, true
+	}
+	return dst.root.first(x, tail, true, reverse)
+}
+
+// extreme returns the smallest key below the node, or the largest when max is
+// true, where key holds the bits of the path to the node, and mask is the bit
+// of the key below the node. It returns false when it reaches a branch that
+// leads to no key.
+func (n *bnode) extreme(key, mask, tail uint64, max bool) (uint64, bool) {
+	for ; mask > tail; mask >>= 1 {
+		first, second := n.left, n.right
+		if max {
+			first, second = second, first
+		}
+		switch {
+		case first != nil:
+			n = first
+			if max {
+				key |= mask
+			}
+		case second != nil:
+			n = second
+			if !max {
+				key |= mask
+			}
+		default:
+			return 0, false
+		}
+	}
+	return bitmapExtreme(n.leaves(tail), key, max)
+}
+
+// first returns the first key that walk would give its callback.
+func (n *bnode) first(start, tail uint64, bounded, reverse bool) (uint64, bool) {
+	var key uint64
+	var found bool
+	n.walk(0, initialMask, tail, start, bounded, reverse, func(k uint64) bool {
+		key, found = k, true
+		return false
+	})
+	return key, found
+}
+
+// Min returns the smallest key in the tree, and false when the tree is empty.
+func (tree *Charlie) Min() (uint64, bool) {
+	return tree.extreme(false)
+}
+
+// Max returns the largest key in the tree, and false when the tree is empty.
+func (tree *Charlie) Max() (uint64, bool) {
+	return tree.extreme(true)
+}
+
+// Successor returns the smallest key in the tree that is not less than x, and
+// false when there is none.
+func (tree *Charlie) Successor(x uint64) (uint64, bool) {
+	return tree.neighbor(x, false)
+}
+
+// Predecessor returns the largest key in the tree that is not greater than x,
+// and false when there is none.
+func (tree *Charlie) Predecessor(x uint64) (uint64, bool) {
+	return tree.neighbor(x, true)
+}
+
+func (tree *Charlie) extreme(max bool) (uint64, bool) {
+	if tree.head == nil {
+		return 0, false
+	}
+	if key, ok := tree.extremeBelow(tree.head, 0, 0, max); ok {
+		return key, true
+	}
+	return tree.first(0, false, max)
+}
+
+// neighbor returns the key nearest to x, on or after it when reverse is false,
+// and on or before it when reverse is true.
+func (tree *Charlie) neighbor(x uint64, reverse bool) (uint64, bool) {
+	if tree.head == nil {
+		return 0, false
+	}
+
+	// Follow the path of x, remembering the deepest branch that leaves it
+	// toward the side of x that is wanted, and the nearest such branch at that
+	// level.
+	end := tree.endLevel()
+	node := tree.head
+	var alt *cnode
+	var altKey uint64
+	var altLevel int
+	for level := 0; level < end && node != nil; level++ {
+		shift := tree.shift(level)
+		digit := (x >> shift) & tree.mask
+		prefix := x &^ (tree.mask<<shift | (1<<shift - 1)) // digits above this level
+		if d, child := tree.sibling(node, digit, reverse); child != nil {
+			alt, altKey, altLevel = child, prefix|d<<shift, level+1
+		}
+		node = tree.child(node, digit)
+	}
+	if node != nil {
+		if tree.leafBits == 0 {
+			return x, true
+		}
+		// The keys in the bitmap of node, on the wanted side of x.
+		lo, hi := x, ^uint64(0)
+		if reverse {
+			lo, hi = 0, x
+		}
+		base := x &^ (1<<tree.leafBits - 1)
+		if key, ok := bitmapExtreme(node.word&bitmapRange(base, lo, hi), base, reverse); ok {
+			return key, true
+		}
+	}
+	if alt == nil {
+		return 0, false
+	}
+	if key, ok := tree.extremeBelow(alt, altLevel, altKey, reverse); ok {
+		return key, true
+	}
+	return tree.first(x, true, reverse)
+}
+
+// extremeBelow returns the smallest key below the node, or the largest when
+// max is true, where the node is at the specified level, and key holds the
+// digits of the path to it. It returns false when it reaches a branch that
+// leads to no key.
+func (tree *Charlie) extremeBelow(n *cnode, level int, key uint64, max bool) (uint64, bool) {
+	for end := tree.endLevel(); level < end; level++ {
+		shift := tree.shift(level)
+		var next *cnode
+		tree.eachChild(n, max, func(digit uint64, child *cnode) bool {
+			next, key = child, key|digit<<shift
+			return false
+		})
+		if next == nil {
+			return 0, false
+		}
+		n = next
+	}
+	if tree.leafBits != 0 {
+		return bitmapExtreme(n.word, key, max)
+	}
+	return key, true
+}
+
+// first returns the first key that walk would give its callback.
+func (tree *Charlie) first(start uint64, bounded, reverse bool) (uint64, bool) {
+	var key uint64
+	var found bool
+	tree.walk(tree.head, 0, 0, start, bounded, reverse, func(k uint64) bool {
+		key, found = k, true
+		return false
+	})
+	return key, found
+}
@@ -0,0 +1,121 @@
+package goradix
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestUint64Order(t *testing.T) {
+	// Deleting without pruning leaves branches that lead to no key, which
+	// the queries must find their way out of.
+	keys := testKeys(300)
+	live := append([]uint64(nil), keys[100:]...)
+	sort.Slice(live, func(i, j int) bool { return live[i] < live[j] })
+
+	probes := []uint64{0, 1, ^uint64(0), 1 << 63}
+	for _, key := range keys {
+		probes = append(probes, key, key-1, key+1)
+	}
+
+	for name, trie := range uint64Tries() {
+		for _, key := range keys {
+			trie.Store(key)
+		}
+		for _, key := range keys[:100] {
+			trie.Delete(key)
+		}
+
+		if got, ok := trie.Min(); !ok || got != live[0] {
+			t.Errorf("%s: Min: GOT: %#x, %v; WANT: %#x, %v", name, got, ok, live[0], true)
+		}
+		if got, ok := trie.Max(); !ok || got != live[len(live)-1] {
+			t.Errorf("%s: Max: GOT: %#x, %v; WANT: %#x, %v", name, got, ok, live[len(live)-1], true)
+		}
+
+		for _, x := range probes {
+			i := sort.Search(len(live), func(i int) bool { return live[i] >= x })
+			want, wantOK := uint64(0), i < len(live)
+			if wantOK {
+				want = live[i]
+			}
+			if got, ok := trie.Successor(x); got != want || ok != wantOK {
+				t.Errorf("%s: Successor(%#x): GOT: %#x, %v; WANT: %#x, %v", name, x, got, ok, want, wantOK)
+			}
+
+			i = sort.Search(len(live), func(i int) bool { return live[i] > x }) - 1
+			want, wantOK = 0, i >= 0
+			if wantOK {
+				want = live[i]
+			}
+			if got, ok := trie.Predecessor(x); got != want || ok != wantOK {
+				t.Errorf("%s: Predecessor(%#x): GOT: %#x, %v; WANT: %#x, %v", name, x, got, ok, want, wantOK)
+			}
+		}
+	}
+}
+
+func TestUint64OrderNeighbors(t *testing.T) {
+	// 0x40 and 0x7f share a bitmap of the final 6 bits, and 0x80 follows
+	// them in the next one.
+	cases := []struct {
+		x, successor, predecessor uint64
+		hasSuccessor              bool
+		hasPredecessor            bool
+	}{
+		{0x3f, 0x40, 0, true, false},
+		{0x41, 0x7f, 0x40, true, true},
+		{0x7e, 0x7f, 0x40, true, true},
+		{0x81, 1 << 32, 0x80, true, true},
+		{1<<32 - 1, 1 << 32, 0x80, true, true},
+		{1<<32 + 1, 0, 1 << 32, false, true},
+	}
+	for name, trie := range uint64Tries() {
+		for _, key := range []uint64{0x40, 0x7f, 0x80, 1 << 32} {
+			trie.Store(key)
+		}
+		for _, c := range cases {
+			if got, ok := trie.Successor(c.x); got != c.successor || ok != c.hasSuccessor {
+				t.Errorf("%s: Successor(%#x): GOT: %#x, %v; WANT: %#x, %v", name, c.x, got, ok, c.successor, c.hasSuccessor)
+			}
+			if got, ok := trie.Predecessor(c.x); got != c.predecessor || ok != c.hasPredecessor {
+				t.Errorf("%s: Predecessor(%#x): GOT: %#x, %v; WANT: %#x, %v", name, c.x, got, ok, c.predecessor, c.hasPredecessor)
+			}
+		}
+	}
+}
+
+func TestUint64OrderEmpty(t *testing.T) {
+	for name, trie := range uint64Tries() {
+		trie.Store(42)
+		trie.Delete(42)
+
+		if _, ok := trie.Min(); ok {
+			t.Errorf("%s: Min: GOT: %v; WANT: %v", name, ok, false)
+		}
+		if _, ok := trie.Max(); ok {
+			t.Errorf("%s: Max: GOT: %v; WANT: %v", name, ok, false)
+		}
+		if _, ok := trie.Successor(0); ok {
+			t.Errorf("%s: Successor: GOT: %v; WANT: %v", name, ok, false)
+		}
+		if _, ok := trie.Predecessor(^uint64(0)); ok {
+			t.Errorf("%s: Predecessor: GOT: %v; WANT: %v", name, ok, false)
+		}
+	}
+}
+
+func TestBravoSuccessorCompacted(t *testing.T) {
+	dst := NewBravo()
+	dst.SetPruneOnDelete(true)
+	for _, key := range []uint64{10, 20, 30} {
+		dst.Store(key)
+	}
+	dst.Delete(20)
+
+	if got, ok := dst.Successor(11); !ok || got != 30 {
+		t.Errorf("GOT: %v, %v; WANT: %v, %v", got, ok, 30, true)
+	}
+	if got, ok := dst.Predecessor(29); !ok || got != 10 {
+		t.Errorf("GOT: %v, %v; WANT: %v, %v", got, ok, 10, true)
+	}
+}
@@ -0,0 +1,232 @@
+package goradix
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// SkipDir may be returned by a PathWalkFunc to cause PathTrie.Walk to skip the
+// paths below the path for which it was returned. It is not returned as an
+// error by Walk.
+var SkipDir = errors.New("skip this directory")
+
+// PathWalkFunc is the type of function invoked by PathTrie.Walk for each path
+// it visits.
+type PathWalkFunc func(path string, value interface{}) error
+
+// PathTrie stores values for hierarchical keys whose segments are delimited by
+// a separator, such as file system paths or URL paths. Operations that take a
+// directory only consider paths below that directory at a segment boundary, so
+// "/a/bc" is never treated as being below "/a/b".
+type PathTrie struct {
+	trie      Alpha
+	separator string
+}
+
+// NewPathTrie returns a new PathTrie whose path segments are delimited by the
+// specified separator. When separator is empty, "/" is used.
+func NewPathTrie(separator string) *PathTrie {
+	if separator == "" {
+		separator = "/"
+	}
+	return &PathTrie{separator: separator}
+}
+
+// dir returns the prefix shared by all paths below the specified directory.
+func (pt *PathTrie) dir(path string) string {
+	if path == "" || strings.HasSuffix(path, pt.separator) {
+		return path
+	}
+	return path + pt.separator
+}
+
+// Delete removes the specified path and its value from the PathTrie. Paths below
+// it are not removed.
+func (pt *PathTrie) Delete(path string) {
+	pt.trie.Delete(path)
+}
+
+// DeleteDir removes the specified path and every path below it from the
+// PathTrie, and returns the number of paths removed.
+func (pt *PathTrie) DeleteDir(path string) int {
+	var count int
+	if _, ok := pt.trie.Load(path); ok {
+		pt.trie.Delete(path)
+		count++
+	}
+	return count + pt.trie.deletePrefix(pt.dir(path))
+}
+
+// Load returns the value associated with the specified path, along with a
+// boolean which is true when the PathTrie has the specified path.
+func (pt *PathTrie) Load(path string) (interface{}, bool) {
+	return pt.trie.Load(path)
+}
+
+// Store stores the specified path and value in the PathTrie.
+func (pt *PathTrie) Store(path string, value interface{}) {
+	pt.trie.Store(path, value)
+}
+
+// Children returns the sorted names of the segments immediately below the
+// specified directory, much like listing a directory. A name is returned when
+// the directory joined with that name is a stored path, or when there are
+// stored paths below it. Use the separator as the path to list the segments
+// immediately below the root of paths that begin with the separator.
+func (pt *PathTrie) Children(path string) []string {
+	dir := pt.dir(path)
+
+	curr, extra := pt.trie.keysFindStartingNode(dir, nil)
+	if curr == nil {
+		return nil
+	}
+	prefix := dir + curr.prefix[len(curr.prefix)-extra:]
+
+	var names []string
+	collect := func(key string, _ interface{}, isPrefix bool) bool {
+		name := key[len(dir):]
+		if isPrefix {
+			name = name[:len(name)-len(pt.separator)]
+		}
+		if len(name) > 0 || isPrefix {
+			names = append(names, name)
+		}
+		return true
+	}
+
+	if i := indexDelimiter(prefix, len(dir), len(dir), pt.separator); i >= 0 {
+		collect(prefix[:i+len(pt.separator)], nil, true)
+	} else {
+		curr.walkDelimited(prefix, len(dir), pt.separator, "", collect)
+	}
+
+	// A name is found once as a stored path and once more as a directory when
+	// there are paths below it. Because paths are visited in byte order, those
+	// two need not be adjacent.
+	sort.Strings(names)
+	var j int
+	for i, name := range names {
+		if i > 0 && name == names[j-1] {
+			continue
+		}
+		names[j] = name
+		j++
+	}
+	return names[:j]
+}
+
+// LongestPathPrefix returns the longest stored path that is either the specified
+// path or a directory that contains it, along with its value and a boolean
+// which is true when such a path was found. Stored paths only match at a
+// segment boundary, so "/a/b" matches "/a/b/c" but not "/a/bc".
+func (pt *PathTrie) LongestPathPrefix(path string) (string, interface{}, bool) {
+	sep := pt.separator
+	length, node := pt.trie.longestPrefix(path, func(length int) bool {
+		return length == len(path) ||
+			strings.HasSuffix(path[:length], sep) ||
+			strings.HasPrefix(path[length:], sep)
+	})
+	if node == nil {
+		return "", nil, false
+	}
+	return path[:length], node.value, true
+}
+
+// Walk invokes fn for the specified path, when it is stored, and for every
+// stored path below it, in ascending byte order. When fn returns SkipDir for a
+// path, the paths below that path are not visited. When fn returns any other
+// non-nil error, Walk stops and returns that error. An empty path walks the
+// entire PathTrie.
+func (pt *PathTrie) Walk(path string, fn PathWalkFunc) error {
+	w := &pathWalker{
+		path:      path,
+		dir:       pt.dir(path),
+		separator: pt.separator,
+		fn:        fn,
+	}
+
+	curr, extra := pt.trie.keysFindStartingNode(path, nil)
+	if curr == nil {
+		return nil
+	}
+	prefix := path + curr.prefix[len(curr.prefix)-extra:]
+	if !w.wants(prefix) {
+		return nil
+	}
+	return w.walk(curr, prefix)
+}
+
+type pathWalker struct {
+	path      string   // path being walked
+	dir       string   // prefix of all paths below path
+	skips     []string // prefixes of the paths below each skipped directory
+	separator string
+	fn        PathWalkFunc
+}
+
+// wants returns true when key may be, or may lead to, a path being walked.
+func (w *pathWalker) wants(key string) bool {
+	if w.skipped(key) {
+		return false
+	}
+	return strings.HasPrefix(key, w.dir) || strings.HasPrefix(w.dir, key)
+}
+
+// skipped returns true when key is below a directory for which fn returned
+// SkipDir. Because paths are visited in byte order, the paths below "/a" may
+// follow "/a-b" and the paths below it, so every skipped directory is kept
+// until a key sorts after all of the paths below it. Keys must therefore be
+// checked in ascending order.
+func (w *pathWalker) skipped(key string) bool {
+	var j int
+	for _, skip := range w.skips {
+		if strings.HasPrefix(key, skip) {
+			return true
+		}
+		if key < skip {
+			w.skips[j] = skip
+			j++
+		}
+	}
+	w.skips = w.skips[:j]
+	return false
+}
+
+func (w *pathWalker) walk(node *Alpha, prefix string) error {
+	for _, child := range node.children {
+		if child.prefix == "" {
+			if err := w.visit(prefix, child.value); err != nil {
+				return err
+			}
+			continue // data node does not have children
+		}
+		key := prefix + child.prefix
+		if !w.wants(key) {
+			continue
+		}
+		if err := w.walk(child, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *pathWalker) visit(key string, value interface{}) error {
+	if key != w.path && !strings.HasPrefix(key, w.dir) {
+		return nil
+	}
+	if w.skipped(key) {
+		return nil
+	}
+	err := w.fn(key, value)
+	if err == SkipDir {
+		skip := key
+		if !strings.HasSuffix(key, w.separator) {
+			skip += w.separator
+		}
+		w.skips = append(w.skips, skip)
+		return nil
+	}
+	return err
+}
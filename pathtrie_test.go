@@ -0,0 +1,206 @@
+package goradix
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPathTrieChildren(t *testing.T) {
+	pt := NewPathTrie("/")
+	pt.Store("/a", 0)
+	pt.Store("/a/b", 1)
+	pt.Store("/a/b/c", 2)
+	pt.Store("/a/b/d/e", 3)
+	pt.Store("/a/b.txt", 4)
+	pt.Store("/a/bc", 5)
+	pt.Store("/a/bc/d", 6)
+	pt.Store("/z", 7)
+
+	cases := []struct {
+		path string
+		want []string
+	}{
+		{"/", []string{"a", "z"}},
+		{"/a", []string{"b", "b.txt", "bc"}},
+		{"/a/", []string{"b", "b.txt", "bc"}},
+		{"/a/b", []string{"c", "d"}},
+		{"/a/b/d", []string{"e"}},
+		{"/a/b/c", nil},
+		{"/a/x", nil},
+		{"/q", nil},
+	}
+
+	for _, item := range cases {
+		if got, want := pt.Children(item.path), item.want; !reflect.DeepEqual(got, want) {
+			t.Errorf("%q; GOT: %q; WANT: %q", item.path, got, want)
+		}
+	}
+}
+
+func TestPathTrieLongestPathPrefix(t *testing.T) {
+	pt := NewPathTrie("/")
+	pt.Store("/a", 0)
+	pt.Store("/a/b", 1)
+	pt.Store("/a/b/c", 2)
+	pt.Store("/a/b/d/e", 3)
+	pt.Store("/a/b.txt", 4)
+	pt.Store("/a/bc", 5)
+	pt.Store("/a/bc/d", 6)
+	pt.Store("/z", 7)
+
+	cases := []struct {
+		path, want string
+		ok         bool
+	}{
+		{"/a/b/c", "/a/b/c", true},
+		{"/a/b/c/d", "/a/b/c", true},
+		{"/a/b/d", "/a/b", true},
+		{"/a/bcd", "/a", true},
+		{"/a/bc/d/e", "/a/bc/d", true},
+		{"/ab", "", false},
+		{"/y", "", false},
+	}
+
+	for _, item := range cases {
+		got, _, ok := pt.LongestPathPrefix(item.path)
+		if ok != item.ok {
+			t.Errorf("%q; GOT: %v; WANT: %v", item.path, ok, item.ok)
+		}
+		if got != item.want {
+			t.Errorf("%q; GOT: %q; WANT: %q", item.path, got, item.want)
+		}
+	}
+}
+
+func TestPathTrieWalk(t *testing.T) {
+	pt := NewPathTrie("/")
+	pt.Store("/a", 0)
+	pt.Store("/a/b", 1)
+	pt.Store("/a/b/c", 2)
+	pt.Store("/a/b/d/e", 3)
+	pt.Store("/a/b.txt", 4)
+	pt.Store("/a/bc", 5)
+	pt.Store("/a/bc/d", 6)
+	pt.Store("/z", 7)
+
+	t.Run("All", func(t *testing.T) {
+		var got []string
+		err := pt.Walk("", func(path string, _ interface{}) error {
+			got = append(got, path)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"/a", "/a/b", "/a/b.txt", "/a/b/c", "/a/b/d/e", "/a/bc", "/a/bc/d", "/z"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GOT: %q; WANT: %q", got, want)
+		}
+	})
+
+	t.Run("Directory", func(t *testing.T) {
+		var got []string
+		err := pt.Walk("/a/b", func(path string, _ interface{}) error {
+			got = append(got, path)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"/a/b", "/a/b/c", "/a/b/d/e"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GOT: %q; WANT: %q", got, want)
+		}
+	})
+
+	t.Run("SkipDir", func(t *testing.T) {
+		var got []string
+		err := pt.Walk("/a", func(path string, _ interface{}) error {
+			got = append(got, path)
+			if path == "/a/b" {
+				return SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"/a", "/a/b", "/a/b.txt", "/a/bc", "/a/bc/d"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GOT: %q; WANT: %q", got, want)
+		}
+	})
+
+	t.Run("SkipDirInterleaved", func(t *testing.T) {
+		pt := NewPathTrie("/")
+		for i, path := range []string{"/a", "/a/x", "/a-b", "/a-b/c", "/a.b/d"} {
+			pt.Store(path, i)
+		}
+
+		// Paths below "/a" follow "/a-b" and the paths below it, because '-'
+		// and '.' sort before '/'.
+		var got []string
+		err := pt.Walk("", func(path string, _ interface{}) error {
+			got = append(got, path)
+			if path == "/a" || path == "/a-b" {
+				return SkipDir
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"/a", "/a-b", "/a.b/d"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GOT: %q; WANT: %q", got, want)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		stop := errors.New("stop")
+		var count int
+		err := pt.Walk("", func(path string, _ interface{}) error {
+			count++
+			if path == "/a/b" {
+				return stop
+			}
+			return nil
+		})
+		if got, want := err, stop; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+		if got, want := count, 2; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	})
+}
+
+func TestPathTrieDeleteDir(t *testing.T) {
+	pt := NewPathTrie("/")
+	pt.Store("/a", 0)
+	pt.Store("/a/b", 1)
+	pt.Store("/a/b/c", 2)
+	pt.Store("/a/b/d/e", 3)
+	pt.Store("/a/b.txt", 4)
+	pt.Store("/a/bc", 5)
+	pt.Store("/a/bc/d", 6)
+	pt.Store("/z", 7)
+
+	if got, want := pt.DeleteDir("/a/b"), 3; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+
+	var got []string
+	err := pt.Walk("", func(path string, _ interface{}) error {
+		got = append(got, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"/a", "/a/b.txt", "/a/bc", "/a/bc/d", "/z"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+}
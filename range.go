@@ -0,0 +1,140 @@
+package goradix
+
+// Range invokes fn with each key in the DST, in ascending order, until fn
+// returns false.
+func (dst *Bravo) Range(fn func(key uint64) bool) {
+	if dst.root != nil {
+		dst.root.walk(0, initialMask, dst.tail(), 0, false, false, fn)
+	}
+}
+
+// RangeFrom invokes fn with each key in the DST that is not less than start, in
+// ascending order, until fn returns false.
+func (dst *Bravo) RangeFrom(start uint64, fn func(key uint64) bool) {
+	if dst.root != nil {
+		dst.root.walk(0, initialMask, dst.tail(), start, true, false, fn)
+	}
+}
+
+// ReverseRange invokes fn with each key in the DST, in descending order, until
+// fn returns false.
+func (dst *Bravo) ReverseRange(fn func(key uint64) bool) {
+	if dst.root != nil {
+		dst.root.walk(0, initialMask, dst.tail(), 0, false, true, fn)
+	}
+}
+
+// ReverseRangeFrom invokes fn with each key in the DST that is not greater than
+// start, in descending order, until fn returns false.
+func (dst *Bravo) ReverseRangeFrom(start uint64, fn func(key uint64) bool) {
+	if dst.root != nil {
+		dst.root.walk(0, initialMask, dst.tail(), start, true, true, fn)
+	}
+}
+
+// walk invokes fn with each key below the node, where key holds the bits of
+// the path to the node, and mask is the bit of the key below the node. When
+// bounded is true, the path to the node has the same bits as start, and only
+// keys on the far side of start are skipped. It returns false when fn does.
+func (n *bnode) walk(key, mask, tail, start uint64, bounded, reverse bool, fn func(uint64) bool) bool {
+	if mask <= tail {
+		return walkBitmap(n.leaves(tail), key, start, bounded, reverse, fn)
+	}
+
+	children := [2]*bnode{n.left, n.right}
+	for i := 0; i < 2; i++ {
+		bit := uint64(i)
+		if reverse {
+			bit = 1 - bit
+		}
+		child := children[bit]
+		if child == nil {
+			continue
+		}
+		childBounded := bounded
+		if bounded {
+			var startBit uint64
+			if start&mask != 0 {
+				startBit = 1
+			}
+			switch {
+			case bit == startBit:
+				// The path to the child still has the same bits as start.
+			case (bit < startBit) != reverse:
+				continue // every key below child is on the far side of start
+			default:
+				childBounded = false
+			}
+		}
+		childKey := key
+		if bit == 1 {
+			childKey |= mask
+		}
+		if !child.walk(childKey, mask>>1, tail, start, childBounded, reverse, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Range invokes fn with each key in the tree, in ascending order, until fn
+// returns false.
+func (tree *Charlie) Range(fn func(key uint64) bool) {
+	if tree.head != nil {
+		tree.walk(tree.head, 0, 0, 0, false, false, fn)
+	}
+}
+
+// RangeFrom invokes fn with each key in the tree that is not less than start,
+// in ascending order, until fn returns false.
+func (tree *Charlie) RangeFrom(start uint64, fn func(key uint64) bool) {
+	if tree.head != nil {
+		tree.walk(tree.head, 0, 0, start, true, false, fn)
+	}
+}
+
+// ReverseRange invokes fn with each key in the tree, in descending order,
+// until fn returns false.
+func (tree *Charlie) ReverseRange(fn func(key uint64) bool) {
+	if tree.head != nil {
+		tree.walk(tree.head, 0, 0, 0, false, true, fn)
+	}
+}
+
+// ReverseRangeFrom invokes fn with each key in the tree that is not greater
+// than start, in descending order, until fn returns false.
+func (tree *Charlie) ReverseRangeFrom(start uint64, fn func(key uint64) bool) {
+	if tree.head != nil {
+		tree.walk(tree.head, 0, 0, start, true, true, fn)
+	}
+}
+
+// walk invokes fn with each key below the node, which is at the specified
+// level, where key holds the digits of the path to the node. When bounded is
+// true, the path to the node has the same digits as start, and only keys on
+// the far side of start are skipped. It returns false when fn does.
+func (tree *Charlie) walk(n *cnode, level int, key, start uint64, bounded, reverse bool, fn func(uint64) bool) bool {
+	if level == tree.endLevel() {
+		if tree.leafBits != 0 {
+			return walkBitmap(n.word, key, start, bounded, reverse, fn)
+		}
+		return fn(key)
+	}
+
+	shift := tree.shift(level)
+	startDigit := (start >> shift) & tree.mask
+	return tree.eachChild(n, reverse, func(digit uint64, child *cnode) bool {
+		childBounded := bounded
+		if bounded {
+			switch d := digit; {
+			case d == startDigit:
+				// The path to the child still has the same digits as start.
+			case (d < startDigit) != reverse:
+				return true // every key below child is on the far side of start
+			default:
+				childBounded = false
+			}
+		}
+		return tree.walk(child, level+1, key|digit<<shift, start, childBounded, reverse, fn)
+	})
+}
@@ -0,0 +1,395 @@
+package goradix
+
+import "math/bits"
+
+// SetSubtreeCounts controls whether each node of the DST keeps the number of
+// keys below it, so that CountRange may add up whole subtrees rather than
+// visiting each of their keys. Keeping the counts makes Store and Delete
+// update every node on the path of their key. Turning the counts on computes
+// the count of every node already in the DST.
+func (dst *Bravo) SetSubtreeCounts(on bool) {
+	if on == dst.counting {
+		return
+	}
+	dst.counting = on
+	if on && dst.root != nil {
+		dst.root.recount(initialMask, dst.tail())
+	}
+}
+
+// adjustCounts adds delta to the count of every node on the path of the key,
+// which must be present in the DST. The nodes at which keys end have no count
+// to adjust, for they hold a single key, or their bitmap.
+func (dst *Bravo) adjustCounts(key uint64, delta int) {
+	node := dst.root
+	tail := dst.tail()
+	for mask := initialMask; mask > tail; mask >>= 1 {
+		node.word += uint64(delta)
+		if key&mask != 0 {
+			node = node.right
+		} else {
+			node = node.left
+		}
+	}
+}
+
+// recount sets the count of the node, and of every node below it, where mask is
+// the bit of the key below the node, and returns the number of keys below the
+// node.
+func (n *bnode) recount(mask, tail uint64) uint64 {
+	if mask <= tail {
+		return uint64(bits.OnesCount64(n.leaves(tail)))
+	}
+	var count uint64
+	for _, child := range [2]*bnode{n.left, n.right} {
+		if child != nil {
+			count += child.recount(mask>>1, tail)
+		}
+	}
+	n.word = count
+	return count
+}
+
+// size returns the number of keys and the number of nodes below and including
+// the node, where mask is the bit of the key below the node. When counting,
+// the number of keys is the count kept by the node, and only the nodes are
+// visited.
+func (n *bnode) size(mask, tail uint64, counting bool) (keys, nodes uint64) {
+	if mask <= tail {
+		return uint64(bits.OnesCount64(n.leaves(tail))), 1
+	}
+	if counting {
+		return n.word, n.nodeCount(mask, tail)
+	}
+	nodes = 1
+	for _, child := range [2]*bnode{n.left, n.right} {
+		if child != nil {
+			k, m := child.size(mask>>1, tail, false)
+			keys += k
+			nodes += m
+		}
+	}
+	return keys, nodes
+}
+
+// nodeCount returns the number of nodes below and including the node, where
+// mask is the bit of the key below the node.
+func (n *bnode) nodeCount(mask, tail uint64) uint64 {
+	if mask <= tail {
+		return 1
+	}
+	nodes := uint64(1)
+	for _, child := range [2]*bnode{n.left, n.right} {
+		if child != nil {
+			nodes += child.nodeCount(mask>>1, tail)
+		}
+	}
+	return nodes
+}
+
+// CountRange returns the number of keys in the DST that are not less than lo,
+// and not greater than hi. When the DST keeps subtree counts, each subtree
+// whose keys are all in the range is counted without visiting its keys.
+func (dst *Bravo) CountRange(lo, hi uint64) uint64 {
+	if lo > hi || dst.root == nil {
+		return 0
+	}
+	return dst.root.countRange(0, initialMask, dst.tail(), lo, hi, dst.counting)
+}
+
+// countRange returns the number of keys below the node that are in the range,
+// where key holds the bits of the path to the node, and mask is the bit of the
+// key below the node.
+func (n *bnode) countRange(key, mask, tail, lo, hi uint64, counting bool) uint64 {
+	if mask <= tail {
+		return uint64(bits.OnesCount64(n.leaves(tail) & bitmapRange(key, lo, hi)))
+	}
+	if counting && lo <= key && key|(mask<<1-1) <= hi {
+		return n.word
+	}
+	var count uint64
+	if n.left != nil && lo <= key|(mask-1) {
+		count += n.left.countRange(key, mask>>1, tail, lo, hi, counting)
+	}
+	if n.right != nil && key|mask <= hi {
+		count += n.right.countRange(key|mask, mask>>1, tail, lo, hi, counting)
+	}
+	return count
+}
+
+// DeleteRange removes every key from the DST that is not less than lo, and not
+// greater than hi, and returns the number of keys it removed. Each subtree
+// whose keys are all in the range is detached from its parent in one step,
+// and then only visited to count its nodes, and, unless the DST keeps subtree
+// counts, its keys. Nodes left without children by the removal are removed as
+// well.
+func (dst *Bravo) DeleteRange(lo, hi uint64) uint64 {
+	if lo > hi || dst.root == nil {
+		return 0
+	}
+	keys, nodes := dst.root.deleteRange(0, initialMask, dst.tail(), lo, hi, dst.counting)
+	dst.keys -= keys
+	dst.nodes -= nodes
+	dst.Count -= nodes
+	return keys
+}
+
+// deleteRange removes the keys below the node that are in the range, where
+// key holds the bits of the path to the node, and mask is the bit of the key
+// below the node. It returns the number of keys and nodes removed.
+func (n *bnode) deleteRange(key, mask, tail, lo, hi uint64, counting bool) (keys, nodes uint64) {
+	if mask <= tail {
+		// Only a bitmap is partly covered by the range.
+		removed := n.word & bitmapRange(key, lo, hi)
+		n.word &^= removed
+		return uint64(bits.OnesCount64(removed)), 0
+	}
+
+	childMask := mask >> 1
+	for bit := uint64(0); bit < 2; bit++ {
+		child := &n.left
+		childKey := key
+		if bit == 1 {
+			child, childKey = &n.right, key|mask
+		}
+		childHi := childKey | (mask - 1)
+		if *child == nil || childHi < lo || hi < childKey {
+			continue // no key below child is in the range
+		}
+
+		if lo <= childKey && childHi <= hi {
+			// Every key below child is in the range.
+			k, m := (*child).size(childMask, tail, counting)
+			keys += k
+			nodes += m
+			*child = nil
+			continue
+		}
+
+		k, m := (*child).deleteRange(childKey, childMask, tail, lo, hi, counting)
+		keys += k
+		nodes += m
+		if (*child).isEmpty(childMask, tail) {
+			// child leads to no key. A leaf would have been covered by
+			// the range, but a bitmap may have lost its last key.
+			*child = nil
+			nodes++
+		}
+	}
+	if counting {
+		n.word -= keys
+	}
+	return keys, nodes
+}
+
+// SetSubtreeCounts controls whether each node of the tree keeps the number of
+// keys below it, so that CountRange may add up whole subtrees rather than
+// visiting each of their keys. Keeping the counts makes Store and Delete
+// update every node on the path of their key. Turning the counts on computes
+// the count of every node already in the tree.
+func (tree *Charlie) SetSubtreeCounts(on bool) {
+	if on == tree.counting {
+		return
+	}
+	tree.counting = on
+	if on && tree.head != nil {
+		tree.recount(tree.head, 0)
+	}
+}
+
+// keysBelow returns the number of keys below the node, which is at the
+// specified level of a tree that keeps subtree counts.
+func (tree *Charlie) keysBelow(n *cnode, level int) uint64 {
+	if level == tree.endLevel() {
+		return tree.leafCount(n)
+	}
+	return n.word
+}
+
+// adjustCounts adds delta to the count of every node on the path of the key,
+// which must be present in the tree. The nodes at the end level have no count
+// to adjust, for they hold a single key, or their bitmap.
+func (tree *Charlie) adjustCounts(key uint64, delta int) {
+	node := tree.head
+	for bits := tree.bitInit; bits < 64 && bits >= tree.leafBits; bits -= tree.bitStep {
+		node.word += uint64(delta)
+		node = tree.child(node, (key>>bits)&tree.mask)
+	}
+}
+
+// recount sets the count of the node, which is at the specified level, and of
+// every node below it, and returns the number of keys below the node.
+func (tree *Charlie) recount(n *cnode, level int) uint64 {
+	if level == tree.endLevel() {
+		return tree.leafCount(n)
+	}
+	var count uint64
+	for _, child := range n.children {
+		if child != nil {
+			count += tree.recount(child, level+1)
+		}
+	}
+	n.word = count
+	return count
+}
+
+// size returns the number of keys and the number of nodes below and including
+// the node, which is at the specified level. When the tree keeps subtree
+// counts, the number of keys is the count kept by the node, and only the nodes
+// are visited.
+func (tree *Charlie) size(n *cnode, level int) (keys, nodes uint64) {
+	if level == tree.endLevel() {
+		return tree.leafCount(n), 1
+	}
+	if tree.counting {
+		return n.word, tree.nodeCount(n, level)
+	}
+	nodes = 1
+	for _, child := range n.children {
+		if child != nil {
+			k, m := tree.size(child, level+1)
+			keys += k
+			nodes += m
+		}
+	}
+	return keys, nodes
+}
+
+// nodeCount returns the number of nodes below and including the node, which is
+// at the specified level.
+func (tree *Charlie) nodeCount(n *cnode, level int) uint64 {
+	if level == tree.endLevel() {
+		return 1
+	}
+	nodes := uint64(1)
+	for _, child := range n.children {
+		if child != nil {
+			nodes += tree.nodeCount(child, level+1)
+		}
+	}
+	return nodes
+}
+
+// span returns the smallest and largest keys that may be found below the node
+// that is reached from a node at the specified level by following digit, where
+// key holds the digits of the path to the node at level.
+func (tree *Charlie) span(level int, key, digit uint64) (uint64, uint64) {
+	shift := tree.shift(level)
+	childKey := key | digit<<shift
+	return childKey, childKey | (1<<shift - 1)
+}
+
+// CountRange returns the number of keys in the tree that are not less than lo,
+// and not greater than hi. When the tree keeps subtree counts, each subtree
+// whose keys are all in the range is counted without visiting its keys.
+func (tree *Charlie) CountRange(lo, hi uint64) uint64 {
+	if lo > hi || tree.head == nil {
+		return 0
+	}
+	if tree.counting && lo == 0 && hi == ^uint64(0) {
+		return tree.head.word
+	}
+	return tree.countRange(tree.head, 0, 0, lo, hi)
+}
+
+// countRange returns the number of keys below the node, which is at the
+// specified level, that are in the range, where key holds the digits of the
+// path to the node.
+func (tree *Charlie) countRange(n *cnode, level int, key, lo, hi uint64) uint64 {
+	if level == tree.endLevel() {
+		if tree.leafBits != 0 {
+			return uint64(bits.OnesCount64(n.word & bitmapRange(key, lo, hi)))
+		}
+		return 1 // the parent only visits children within the range
+	}
+	var count uint64
+	tree.eachChild(n, false, func(digit uint64, child *cnode) bool {
+		childLo, childHi := tree.span(level, key, digit)
+		switch {
+		case childHi < lo || hi < childLo:
+			// no key below child is in the range
+		case tree.counting && lo <= childLo && childHi <= hi:
+			count += tree.keysBelow(child, level+1)
+		default:
+			count += tree.countRange(child, level+1, childLo, lo, hi)
+		}
+		return true
+	})
+	return count
+}
+
+// DeleteRange removes every key from the tree that is not less than lo, and
+// not greater than hi, and returns the number of keys it removed. Each subtree
+// whose keys are all in the range is detached from its parent in one step, and
+// then only visited to count its nodes, and, unless the tree keeps subtree
+// counts, its keys. Nodes left without children by the removal are removed as
+// well.
+func (tree *Charlie) DeleteRange(lo, hi uint64) uint64 {
+	if lo > hi || tree.head == nil {
+		return 0
+	}
+	keys, nodes := tree.deleteRange(tree.head, 0, 0, lo, hi)
+	tree.keys -= keys
+	tree.nodes -= nodes
+	tree.Count -= keys
+	return keys
+}
+
+// deleteRange removes the keys below the node, which is at the specified level,
+// that are in the range, where key holds the digits of the path to the node.
+// It returns the number of keys and nodes removed.
+func (tree *Charlie) deleteRange(n *cnode, level int, key, lo, hi uint64) (keys, nodes uint64) {
+	if level == tree.endLevel() {
+		// Only a bitmap is partly covered by the range.
+		removed := n.word & bitmapRange(key, lo, hi)
+		n.word &^= removed
+		return uint64(bits.OnesCount64(removed)), 0
+	}
+
+	// The children are visited in reverse, so that removing one leaves those
+	// still to be visited where they are.
+	tree.eachChild(n, true, func(digit uint64, child *cnode) bool {
+		childLo, childHi := tree.span(level, key, digit)
+		if childHi < lo || hi < childLo {
+			return true // no key below child is in the range
+		}
+
+		if lo <= childLo && childHi <= hi {
+			// Every key below child is in the range.
+			k, m := tree.size(child, level+1)
+			keys += k
+			nodes += m
+			tree.setChild(n, digit, nil)
+			return true
+		}
+
+		k, m := tree.deleteRange(child, level+1, childLo, lo, hi)
+		keys += k
+		nodes += m
+		if !tree.hasChild(child, level+1) {
+			// child leads to no key. A leaf would have been covered by
+			// the range, but a bitmap may have lost its last key.
+			tree.setChild(n, digit, nil)
+			nodes++
+		}
+		return true
+	})
+	if tree.counting {
+		n.word -= keys
+	}
+	return keys, nodes
+}
+
+// hasChild returns true when the node, which is at the specified level, has at
+// least one child, or at least one key in its bitmap.
+func (tree *Charlie) hasChild(n *cnode, level int) bool {
+	if level < tree.endLevel() {
+		for _, child := range n.children {
+			if child != nil {
+				return true
+			}
+		}
+		return false
+	}
+	return n.word != 0
+}
@@ -0,0 +1,166 @@
+package goradix
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestUint64CountAndDeleteRange(t *testing.T) {
+	keys := testKeys(400)
+	for _, counting := range []bool{false, true} {
+		for name, trie := range uint64Tries() {
+			// Turn the counts on between storing and deleting keys, so that
+			// they are computed for the keys already stored, and then kept
+			// up to date.
+			for _, key := range keys {
+				trie.Store(key)
+			}
+			trie.SetSubtreeCounts(counting)
+			for _, key := range keys[:100] {
+				trie.Delete(key)
+			}
+
+			sorted := append([]uint64(nil), keys[100:]...)
+			sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+			inRange := func(lo, hi uint64) uint64 {
+				var count uint64
+				for _, key := range sorted {
+					if lo <= key && key <= hi {
+						count++
+					}
+				}
+				return count
+			}
+
+			bounds := [][2]uint64{
+				{0, ^uint64(0)},
+				{1, 0},
+				{sorted[3], sorted[3]},
+				{sorted[3] + 1, sorted[len(sorted)/2]},
+				{0, 1<<32 - 1},
+				{1 << 40, 1<<63 - 1},
+			}
+			for _, b := range bounds {
+				if got, want := trie.CountRange(b[0], b[1]), inRange(b[0], b[1]); got != want {
+					t.Errorf("%s, %v: CountRange(%#x, %#x): GOT: %v; WANT: %v", name, counting, b[0], b[1], got, want)
+				}
+			}
+
+			// Delete ranges, checking what is left after each.
+			for _, b := range [][2]uint64{{sorted[3] + 1, sorted[len(sorted)/2]}, {0, 1<<32 - 1}, {1 << 62, ^uint64(0)}} {
+				want := inRange(b[0], b[1])
+				if got := trie.DeleteRange(b[0], b[1]); got != want {
+					t.Errorf("%s, %v: DeleteRange(%#x, %#x): GOT: %v; WANT: %v", name, counting, b[0], b[1], got, want)
+				}
+				var kept []uint64
+				for _, key := range sorted {
+					if key < b[0] || b[1] < key {
+						kept = append(kept, key)
+					} else if trie.Load(key) {
+						t.Errorf("%s, %v: Load(%#x): GOT: %v; WANT: %v", name, counting, key, true, false)
+					}
+				}
+				sorted = kept
+
+				if got, want := trie.Len(), uint64(len(sorted)); got != want {
+					t.Errorf("%s, %v: Len: GOT: %v; WANT: %v", name, counting, got, want)
+				}
+				if got, want := trie.Nodes(), trie.Stats().Nodes; got != want {
+					t.Errorf("%s, %v: Nodes: GOT: %v; WANT: %v", name, counting, got, want)
+				}
+				if got, want := trie.CountRange(0, ^uint64(0)), uint64(len(sorted)); got != want {
+					t.Errorf("%s, %v: CountRange: GOT: %v; WANT: %v", name, counting, got, want)
+				}
+			}
+			for _, key := range sorted {
+				if !trie.Load(key) {
+					t.Errorf("%s, %v: Load(%#x): GOT: %v; WANT: %v", name, counting, key, false, true)
+				}
+			}
+		}
+	}
+}
+
+func TestUint64CountAndDeleteRangeWithinLeaves(t *testing.T) {
+	// 0x40, 0x41 and 0x7f share a bitmap of the final 6 bits, which the
+	// ranges only partly cover.
+	for _, counting := range []bool{false, true} {
+		for name, trie := range uint64Tries() {
+			trie.SetSubtreeCounts(counting)
+			for _, key := range []uint64{0x40, 0x41, 0x7f, 0x80, 0x100} {
+				trie.Store(key)
+			}
+			if got, want := trie.CountRange(0x41, 0x7f), uint64(2); got != want {
+				t.Errorf("%s, %v: GOT: %v; WANT: %v", name, counting, got, want)
+			}
+			if got, want := trie.CountRange(0x42, 0x7e), uint64(0); got != want {
+				t.Errorf("%s, %v: GOT: %v; WANT: %v", name, counting, got, want)
+			}
+			if got, want := trie.DeleteRange(0x41, 0x80), uint64(3); got != want {
+				t.Errorf("%s, %v: GOT: %v; WANT: %v", name, counting, got, want)
+			}
+			if got, want := trie.CountRange(0, ^uint64(0)), uint64(2); got != want {
+				t.Errorf("%s, %v: GOT: %v; WANT: %v", name, counting, got, want)
+			}
+			if got, want := trie.Load(0x40), true; got != want {
+				t.Errorf("%s, %v: GOT: %v; WANT: %v", name, counting, got, want)
+			}
+			if got, want := trie.Nodes(), trie.Stats().Nodes; got != want {
+				t.Errorf("%s, %v: Nodes: GOT: %v; WANT: %v", name, counting, got, want)
+			}
+		}
+	}
+}
+
+func TestBravoSubtreeCountsWithPruning(t *testing.T) {
+	dst := NewBravo()
+	dst.SetSubtreeCounts(true)
+	dst.SetPruneOnDelete(true)
+	for key := uint64(0); key < 100; key++ {
+		dst.Store(key * 3)
+	}
+	for key := uint64(0); key < 100; key += 2 {
+		dst.Delete(key * 3)
+	}
+	dst.Compact()
+
+	if got, want := dst.CountRange(0, 150), uint64(25); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := dst.root.word, uint64(50); got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestSubtreeCountsToggle(t *testing.T) {
+	tries := map[string]uint64Trie{
+		"bravo":          NewBravo(),
+		"bitmap-bravo":   NewBitmapBravo(),
+		"charlie-4":      NewCharlie(4),
+		"bitmap-charlie": NewBitmapCharlie(4),
+		"sparse-charlie": NewSparseCharlie(4),
+	}
+	for name, trie := range tries {
+		for key := uint64(0); key < 100; key++ {
+			trie.Store(key * 0x0123456789)
+		}
+		trie.SetSubtreeCounts(true)
+		if got, want := trie.CountRange(0, 50*0x0123456789), uint64(51); got != want {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, got, want)
+		}
+
+		// The counts go stale while they are off, and are computed afresh
+		// when they are turned back on.
+		trie.SetSubtreeCounts(false)
+		for key := uint64(0); key < 100; key += 2 {
+			trie.Delete(key * 0x0123456789)
+		}
+		trie.SetSubtreeCounts(true)
+		if got, want := trie.CountRange(0, 50*0x0123456789), uint64(25); got != want {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, got, want)
+		}
+		if got, want := trie.CountRange(0, ^uint64(0)), uint64(50); got != want {
+			t.Errorf("%s: GOT: %v; WANT: %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package goradix
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// uint64Trie is implemented by both Bravo and Charlie.
+type uint64Trie interface {
+	Store(uint64)
+	Delete(uint64)
+	Load(uint64) bool
+	Len() uint64
+	Nodes() uint64
+	Stats() Stats
+	Range(func(uint64) bool)
+	RangeFrom(uint64, func(uint64) bool)
+	ReverseRange(func(uint64) bool)
+	ReverseRangeFrom(uint64, func(uint64) bool)
+	Min() (uint64, bool)
+	Max() (uint64, bool)
+	Successor(uint64) (uint64, bool)
+	Predecessor(uint64) (uint64, bool)
+	SetSubtreeCounts(bool)
+	CountRange(lo, hi uint64) uint64
+	DeleteRange(lo, hi uint64) uint64
+}
+
+// uint64Tries returns a new, empty Bravo, and a new, empty Charlie of each
+// width, both with and without bitmap leaves, and with sparse nodes.
+func uint64Tries() map[string]uint64Trie {
+	return map[string]uint64Trie{
+		"bravo":            NewBravo(),
+		"bravo-bitmap":     NewBitmapBravo(),
+		"charlie-1":        NewCharlie(1),
+		"charlie-2":        NewCharlie(2),
+		"charlie-4":        NewCharlie(4),
+		"charlie-8":        NewCharlie(8),
+		"charlie-1-bitmap": NewBitmapCharlie(1),
+		"charlie-2-bitmap": NewBitmapCharlie(2),
+		"charlie-4-bitmap": NewBitmapCharlie(4),
+		"charlie-8-bitmap": NewBitmapCharlie(8),
+		"charlie-1-sparse": NewSparseCharlie(1),
+		"charlie-4-sparse": NewSparseCharlie(4),
+		"charlie-6-sparse": NewSparseCharlie(6),
+		"charlie-8-sparse": NewSparseCharlie(8),
+	}
+}
+
+// testKeys returns the specified number of distinct keys, which are the same
+// on every call. Each key is a byte at a random shift, so that the keys share
+// long runs of zero bits, and end at every depth of a trie.
+func testKeys(count int) []uint64 {
+	rng := rand.New(rand.NewSource(1))
+	keys := make([]uint64, 0, count)
+	seen := make(map[uint64]bool)
+	for len(keys) < count {
+		key := uint64(rng.Intn(256)) << uint(rng.Intn(57))
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// collect returns the keys given to fn by the range function, stopping after
+// limit keys when limit is greater than 0.
+func collect(limit int, rangeFn func(func(uint64) bool)) []uint64 {
+	var keys []uint64
+	rangeFn(func(key uint64) bool {
+		keys = append(keys, key)
+		return limit <= 0 || len(keys) < limit
+	})
+	return keys
+}
+
+func TestUint64Range(t *testing.T) {
+	keys := append(testKeys(200), 0, ^uint64(0))
+	deleted := keys[:50]
+	live := append([]uint64(nil), keys[50:]...)
+	sort.Slice(live, func(i, j int) bool { return live[i] < live[j] })
+	reversed := make([]uint64, len(live))
+	for i, key := range live {
+		reversed[len(live)-1-i] = key
+	}
+
+	for name, trie := range uint64Tries() {
+		for _, key := range keys {
+			trie.Store(key)
+		}
+		for _, key := range deleted {
+			trie.Delete(key)
+		}
+
+		if got := collect(0, trie.Range); !reflect.DeepEqual(got, live) {
+			t.Errorf("%s: Range:\nGOT:  %x\nWANT: %x", name, got, live)
+		}
+		if got := collect(0, trie.ReverseRange); !reflect.DeepEqual(got, reversed) {
+			t.Errorf("%s: ReverseRange:\nGOT:  %x\nWANT: %x", name, got, reversed)
+		}
+		if got, want := collect(3, trie.Range), live[:3]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Range stops:\nGOT:  %x\nWANT: %x", name, got, want)
+		}
+		if got, want := collect(3, trie.ReverseRange), reversed[:3]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: ReverseRange stops:\nGOT:  %x\nWANT: %x", name, got, want)
+		}
+
+		for _, start := range []uint64{0, 1, live[10], live[10] + 1, live[len(live)/2] - 1, ^uint64(0), 1 << 63} {
+			var want []uint64
+			for _, key := range live {
+				if key >= start {
+					want = append(want, key)
+				}
+			}
+			got := collect(0, func(fn func(uint64) bool) { trie.RangeFrom(start, fn) })
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: RangeFrom(%#x):\nGOT:  %x\nWANT: %x", name, start, got, want)
+			}
+
+			want = nil
+			for _, key := range reversed {
+				if key <= start {
+					want = append(want, key)
+				}
+			}
+			got = collect(0, func(fn func(uint64) bool) { trie.ReverseRangeFrom(start, fn) })
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: ReverseRangeFrom(%#x):\nGOT:  %x\nWANT: %x", name, start, got, want)
+			}
+		}
+	}
+}
+
+func TestUint64RangeEmpty(t *testing.T) {
+	for name, trie := range uint64Tries() {
+		trie.Store(42)
+		trie.Delete(42)
+		if got := collect(0, trie.Range); len(got) != 0 {
+			t.Errorf("%s: GOT: %x; WANT: none", name, got)
+		}
+	}
+}
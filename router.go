@@ -0,0 +1,339 @@
+package goradix
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Param is a single path parameter, consisting of the name of the wildcard in
+// the route pattern and the portion of the request path it matched.
+type Param struct {
+	Name, Value string
+}
+
+// Params is the list of path parameters matched by a request, in the order in
+// which their wildcards appear in the route pattern.
+type Params []Param
+
+// ByName returns the value of the first parameter with the specified name, or
+// the empty string when there is no such parameter.
+func (ps Params) ByName(name string) string {
+	for _, p := range ps {
+		if p.Name == name {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+type paramsContextKey struct{}
+
+// ParamsFromContext returns the path parameters that a Router stored in the
+// context of the request it passed to a handler.
+func ParamsFromContext(ctx context.Context) Params {
+	ps, _ := ctx.Value(paramsContextKey{}).(Params)
+	return ps
+}
+
+// Router is an http.Handler that dispatches requests to the handler whose route
+// pattern matches the request method and path.
+//
+// A route pattern is a path that may include named wildcards, each of which
+// must span an entire path segment. A ":name" wildcard matches one non-empty
+// path segment, and a "*name" wildcard, which must end the pattern, matches the
+// rest of the path, including any slashes. For example, "/users/:id/files/*path"
+// matches "/users/42/files/docs/a.txt" with id "42" and path "docs/a.txt".
+//
+// When more than one route matches a request, a static segment takes priority
+// over a ":name" wildcard, which takes priority over a "*name" wildcard.
+type Router struct {
+	// NotFound handles requests that do not match any route. When nil,
+	// http.NotFound is used.
+	NotFound http.Handler
+
+	// RedirectTrailingSlash causes a request for a path that does not match
+	// any route to be redirected to the same path with a trailing slash
+	// added or removed, when that path matches a route. GET and HEAD
+	// requests are redirected with status 301, others with status 308.
+	RedirectTrailingSlash bool
+
+	trees map[string]*routeNode // one tree for each method
+}
+
+// NewRouter returns a new Router that redirects requests for paths whose
+// trailing slash does not match a route.
+func NewRouter() *Router {
+	return &Router{
+		RedirectTrailingSlash: true,
+		trees:                 make(map[string]*routeNode),
+	}
+}
+
+// Handle registers the handler for requests with the specified method and
+// route pattern. It returns an error when the pattern is malformed, or when it
+// conflicts with a route previously registered for the same method.
+func (rt *Router) Handle(method, pattern string, handler http.Handler) error {
+	tokens, err := parseRoutePattern(pattern)
+	if err != nil {
+		return err
+	}
+	if rt.trees == nil {
+		rt.trees = make(map[string]*routeNode)
+	}
+	root, ok := rt.trees[method]
+	if !ok {
+		root = new(routeNode)
+		rt.trees[method] = root
+	}
+
+	n := root
+	for _, token := range tokens {
+		switch token.kind {
+		case routeStatic:
+			n = n.insertStatic(token.text)
+		case routeParam:
+			if n.param == nil {
+				n.param = &routeNode{name: token.text}
+			} else if n.param.name != token.text {
+				return fmt.Errorf("cannot register %s %q: wildcard %q conflicts with %q", method, pattern, ":"+token.text, ":"+n.param.name)
+			}
+			n = n.param
+		case routeCatchAll:
+			if n.catchAll == nil {
+				n.catchAll = &routeNode{name: token.text}
+			} else if n.catchAll.name != token.text {
+				return fmt.Errorf("cannot register %s %q: wildcard %q conflicts with %q", method, pattern, "*"+token.text, "*"+n.catchAll.name)
+			}
+			n = n.catchAll
+		}
+	}
+
+	if n.handler != nil {
+		return fmt.Errorf("cannot register %s %q: conflicts with %q", method, pattern, n.pattern)
+	}
+	n.handler = handler
+	n.pattern = pattern
+	return nil
+}
+
+// HandleFunc registers the handler function for requests with the specified
+// method and route pattern. See Handle.
+func (rt *Router) HandleFunc(method, pattern string, handler func(http.ResponseWriter, *http.Request)) error {
+	return rt.Handle(method, pattern, http.HandlerFunc(handler))
+}
+
+// ServeHTTP dispatches the request to the handler whose route matches it. The
+// handler may obtain the path parameters by calling ParamsFromContext with the
+// context of the request.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	if root := rt.trees[r.Method]; root != nil {
+		var params Params
+		if n := root.match(path, &params); n != nil {
+			if len(params) > 0 {
+				r = r.WithContext(context.WithValue(r.Context(), paramsContextKey{}, params))
+			}
+			n.handler.ServeHTTP(w, r)
+			return
+		}
+
+		if rt.RedirectTrailingSlash && path != "/" {
+			var other string
+			if strings.HasSuffix(path, "/") {
+				other = path[:len(path)-1]
+			} else {
+				other = path + "/"
+			}
+			if root.match(other, new(Params)) != nil {
+				code := http.StatusPermanentRedirect
+				if r.Method == http.MethodGet || r.Method == http.MethodHead {
+					code = http.StatusMovedPermanently
+				}
+				u := *r.URL
+				u.Path = other
+				http.Redirect(w, r, u.String(), code)
+				return
+			}
+		}
+	}
+
+	if allowed := rt.allowed(r.Method, path); allowed != "" {
+		w.Header().Set("Allow", allowed)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if rt.NotFound != nil {
+		rt.NotFound.ServeHTTP(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// allowed returns a comma separated list of the methods other than the
+// specified method that have a route matching the specified path.
+func (rt *Router) allowed(method, path string) string {
+	var methods []string
+	for other, root := range rt.trees {
+		if other != method && root.match(path, new(Params)) != nil {
+			methods = append(methods, other)
+		}
+	}
+	sort.Strings(methods)
+	return strings.Join(methods, ", ")
+}
+
+// routeNode is a node of a Router tree. The static text of a route between two
+// of its wildcards, or between a wildcard and either end of the pattern, is a
+// key of an Alpha, whose value is the node for the route at the end of that
+// text. A node only adds to the Alpha what a radix tree cannot hold: a child
+// for each kind of wildcard, and the handler of the route that ends at it.
+type routeNode struct {
+	static   *Alpha     // static text that may follow this node, to the node after it
+	param    *routeNode // child that matches a ":name" wildcard
+	catchAll *routeNode // child that matches a "*name" wildcard
+	name     string     // name of wildcard when this node matches one
+	handler  http.Handler
+	pattern  string // route pattern registered for handler
+}
+
+// insertStatic returns the node that follows the specified static text from n,
+// creating it as needed.
+func (n *routeNode) insertStatic(text string) *routeNode {
+	if n.static == nil {
+		n.static = new(Alpha)
+	} else if child, ok := n.static.Load(text); ok {
+		return child.(*routeNode)
+	}
+	child := new(routeNode)
+	n.static.Store(text, child)
+	return child
+}
+
+// match returns the node whose route matches the specified path below n, after
+// appending the parameters matched along the way, or nil when no route
+// matches. Static text takes priority over a wildcard, and longer static text
+// over shorter, so the static text that may follow n and is a prefix of the
+// path is tried from the longest to the shortest before the wildcards of n.
+func (n *routeNode) match(path string, params *Params) *routeNode {
+	if path == "" {
+		if n.handler != nil {
+			return n
+		}
+		if n.catchAll != nil && n.catchAll.handler != nil {
+			*params = append(*params, Param{Name: n.catchAll.name})
+			return n.catchAll
+		}
+		return nil
+	}
+
+	if n.static != nil {
+		var lengthsBuf [8]int
+		var childrenBuf [8]*routeNode
+		lengths, children := lengthsBuf[:0], childrenBuf[:0]
+		n.static.prefixes(path, func(length int, value interface{}) bool {
+			lengths = append(lengths, length)
+			children = append(children, value.(*routeNode))
+			return true
+		})
+		for i := len(children) - 1; i >= 0; i-- {
+			if found := children[i].match(path[lengths[i]:], params); found != nil {
+				return found
+			}
+		}
+	}
+
+	if n.param != nil {
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+		if end > 0 {
+			*params = append(*params, Param{Name: n.param.name, Value: path[:end]})
+			if found := n.param.match(path[end:], params); found != nil {
+				return found
+			}
+			*params = (*params)[:len(*params)-1]
+		}
+	}
+
+	if n.catchAll != nil && n.catchAll.handler != nil {
+		*params = append(*params, Param{Name: n.catchAll.name, Value: path})
+		return n.catchAll
+	}
+
+	return nil
+}
+
+type routeTokenKind int
+
+const (
+	routeStatic routeTokenKind = iota
+	routeParam
+	routeCatchAll
+)
+
+type routeToken struct {
+	kind routeTokenKind
+	text string // static text, or name of wildcard
+}
+
+// parseRoutePattern splits a route pattern into its static text and its
+// wildcards, and returns an error when the pattern is malformed.
+func parseRoutePattern(pattern string) ([]routeToken, error) {
+	if !strings.HasPrefix(pattern, "/") {
+		return nil, fmt.Errorf("cannot parse route %q: pattern must begin with a slash", pattern)
+	}
+
+	var tokens []routeToken
+	names := make(map[string]struct{})
+	rest := pattern
+
+	for rest != "" {
+		i := strings.IndexAny(rest, ":*")
+		if i < 0 {
+			tokens = append(tokens, routeToken{kind: routeStatic, text: rest})
+			break
+		}
+		if i > 0 {
+			tokens = append(tokens, routeToken{kind: routeStatic, text: rest[:i]})
+		}
+		if rest[i-1] != '/' {
+			return nil, fmt.Errorf("cannot parse route %q: wildcard must begin a path segment", pattern)
+		}
+
+		kind := routeParam
+		if rest[i] == '*' {
+			kind = routeCatchAll
+		}
+		end := strings.IndexByte(rest[i:], '/')
+		if end < 0 {
+			end = len(rest)
+		} else {
+			end += i
+		}
+		name := rest[i+1 : end]
+
+		switch {
+		case name == "":
+			return nil, fmt.Errorf("cannot parse route %q: wildcard must have a name", pattern)
+		case strings.ContainsAny(name, ":*"):
+			return nil, fmt.Errorf("cannot parse route %q: wildcard must span an entire path segment", pattern)
+		case kind == routeCatchAll && end < len(rest):
+			return nil, fmt.Errorf("cannot parse route %q: catch-all wildcard must end the pattern", pattern)
+		}
+		if _, ok := names[name]; ok {
+			return nil, fmt.Errorf("cannot parse route %q: duplicate wildcard name %q", pattern, name)
+		}
+		names[name] = struct{}{}
+
+		tokens = append(tokens, routeToken{kind: kind, text: name})
+		rest = rest[end:]
+	}
+
+	return tokens, nil
+}
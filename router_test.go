@@ -0,0 +1,191 @@
+package goradix
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// nameHandler returns a handler that writes the name of its route followed by
+// the path parameters of the request.
+func nameHandler(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, name)
+		for _, p := range ParamsFromContext(r.Context()) {
+			fmt.Fprintf(w, " %s=%s", p.Name, p.Value)
+		}
+	}
+}
+
+func TestRouterServeHTTP(t *testing.T) {
+	rt := NewRouter()
+	routes := []struct {
+		method, pattern string
+	}{
+		{http.MethodGet, "/"},
+		{http.MethodGet, "/users"},
+		{http.MethodGet, "/users/new"},
+		{http.MethodGet, "/users/:id"},
+		{http.MethodPut, "/users/:id"},
+		{http.MethodGet, "/users/:id/posts/:post"},
+		{http.MethodGet, "/files/*path"},
+		{http.MethodGet, "/files/readme"},
+	}
+	for _, route := range routes {
+		if err := rt.Handle(route.method, route.pattern, nameHandler(route.method+" "+route.pattern)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		method, path string
+		code         int
+		body         string
+	}{
+		{"GET", "/", 200, "GET /"},
+		{"GET", "/users", 200, "GET /users"},
+		{"GET", "/users/new", 200, "GET /users/new"},
+		{"GET", "/users/42", 200, "GET /users/:id id=42"},
+		{"GET", "/users/newt", 200, "GET /users/:id id=newt"},
+		{"PUT", "/users/42", 200, "PUT /users/:id id=42"},
+		{"GET", "/users/42/posts/7", 200, "GET /users/:id/posts/:post id=42 post=7"},
+		{"GET", "/users/new/posts/7", 200, "GET /users/:id/posts/:post id=new post=7"},
+		{"GET", "/files/docs/a.txt", 200, "GET /files/*path path=docs/a.txt"},
+		{"GET", "/files/readme", 200, "GET /files/readme"},
+		{"GET", "/files/readme/more", 200, "GET /files/*path path=readme/more"},
+		{"GET", "/files/", 200, "GET /files/*path path="},
+		{"GET", "/user", 404, "404 page not found\n"},
+		{"GET", "/users/42/posts", 404, "404 page not found\n"},
+		{"DELETE", "/users/42", 405, "Method Not Allowed\n"},
+	}
+
+	for _, item := range cases {
+		t.Run(item.method+item.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			rt.ServeHTTP(w, httptest.NewRequest(item.method, item.path, nil))
+			if got, want := w.Code, item.code; got != want {
+				t.Errorf("GOT: %v; WANT: %v", got, want)
+			}
+			if got, want := w.Body.String(), item.body; got != want {
+				t.Errorf("GOT: %q; WANT: %q", got, want)
+			}
+		})
+	}
+}
+
+func TestRouterMethodNotAllowed(t *testing.T) {
+	rt := NewRouter()
+	for _, method := range []string{http.MethodGet, http.MethodPut} {
+		if err := rt.Handle(method, "/users/:id", nameHandler(method)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rt.Handle(http.MethodDelete, "/users", nameHandler("DELETE")); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("POST", "/users/42", nil))
+
+	if got, want := w.Code, http.StatusMethodNotAllowed; got != want {
+		t.Errorf("GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := w.Header().Get("Allow"), "GET, PUT"; got != want {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+}
+
+func TestRouterRedirectTrailingSlash(t *testing.T) {
+	rt := NewRouter()
+	routes := []struct {
+		method, pattern string
+	}{
+		{http.MethodGet, "/users"},
+		{http.MethodGet, "/uploads/"},
+		{http.MethodPut, "/users/:id"},
+	}
+	for _, route := range routes {
+		if err := rt.Handle(route.method, route.pattern, nameHandler(route.pattern)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		method, path string
+		code         int
+		location     string
+	}{
+		{"GET", "/users/", 301, "/users"},
+		{"GET", "/uploads", 301, "/uploads/"},
+		{"GET", "/uploads?page=2", 301, "/uploads/?page=2"},
+		{"PUT", "/users/42/", 308, "/users/42"},
+	}
+
+	for _, item := range cases {
+		t.Run(item.method+item.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			rt.ServeHTTP(w, httptest.NewRequest(item.method, item.path, nil))
+			if got, want := w.Code, item.code; got != want {
+				t.Errorf("GOT: %v; WANT: %v", got, want)
+			}
+			if got, want := w.Header().Get("Location"), item.location; got != want {
+				t.Errorf("GOT: %q; WANT: %q", got, want)
+			}
+		})
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		rt.RedirectTrailingSlash = false
+		w := httptest.NewRecorder()
+		rt.ServeHTTP(w, httptest.NewRequest("GET", "/users/", nil))
+		if got, want := w.Code, http.StatusNotFound; got != want {
+			t.Errorf("GOT: %v; WANT: %v", got, want)
+		}
+	})
+}
+
+func TestRouterNotFoundHandler(t *testing.T) {
+	rt := NewRouter()
+	if err := rt.Handle(http.MethodGet, "/", nameHandler("/")); err != nil {
+		t.Fatal(err)
+	}
+	rt.NotFound = nameHandler("custom")
+
+	w := httptest.NewRecorder()
+	rt.ServeHTTP(w, httptest.NewRequest("GET", "/nowhere", nil))
+
+	if got, want := w.Body.String(), "custom"; got != want {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+}
+
+func TestRouterHandleErrors(t *testing.T) {
+	rt := NewRouter()
+	for _, pattern := range []string{"/users/new", "/users/:id", "/files/*path"} {
+		if err := rt.Handle(http.MethodGet, pattern, nameHandler(pattern)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		method, pattern string
+	}{
+		{"GET", "users"},                 // missing leading slash
+		{"GET", "/users/new"},            // duplicate
+		{"GET", "/users/:name"},          // conflicting parameter name
+		{"GET", "/users/:name/posts"},    // conflicting parameter name
+		{"GET", "/files/*rest"},          // conflicting catch-all name
+		{"GET", "/files/*path/more"},     // catch-all not at end
+		{"GET", "/users/id:id"},          // wildcard not at start of segment
+		{"GET", "/users/:id:post"},       // wildcard not spanning segment
+		{"GET", "/users/:"},              // wildcard without name
+		{"GET", "/groups/:id/users/:id"}, // duplicate wildcard name
+	}
+
+	for _, item := range cases {
+		if err := rt.Handle(item.method, item.pattern, nameHandler(item.pattern)); err == nil {
+			t.Errorf("%s %q: GOT: %v; WANT: error", item.method, item.pattern, err)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package goradix
+
+import (
+	"math/bits"
+	"unsafe"
+)
+
+// Stats describes the shape and size of a Trie.
+type Stats struct {
+	// Nodes is the number of nodes in the Trie, including its root node, and,
+	// for Alpha, the nodes that hold its values.
+	Nodes uint64
+
+	// Keys is the number of keys stored in the Trie.
+	Keys uint64
+
+	// MaxDepth is the number of edges followed from the root node to reach the
+	// deepest node.
+	MaxDepth int
+
+	// AverageDepth is the mean number of edges followed from the root node to
+	// find each key, or 0 when the Trie has no keys.
+	AverageDepth float64
+
+	// Fanout is a histogram of the number of children of each node, where
+	// Fanout[n] is the number of nodes that have n children.
+	Fanout []uint64
+
+	// PrefixBytes is the number of bytes in the prefixes of every node. It is
+	// always 0 for Bravo and Charlie, whose edges are bits of the key.
+	PrefixBytes uint64
+
+	// HeapBytes is an estimate of the number of bytes of heap the Trie uses,
+	// from the size of its nodes, their children slices, and their
+	// prefixes. It does not include the values stored in an Alpha.
+	HeapBytes uint64
+}
+
+// node records a node with the specified number of children at depth.
+func (s *Stats) node(depth, children int) {
+	s.Nodes++
+	if depth > s.MaxDepth {
+		s.MaxDepth = depth
+	}
+	for len(s.Fanout) <= children {
+		s.Fanout = append(s.Fanout, 0)
+	}
+	s.Fanout[children]++
+}
+
+// finish computes the average depth from the sum of the depths of each key.
+func (s *Stats) finish(depths uint64) {
+	if s.Keys > 0 {
+		s.AverageDepth = float64(depths) / float64(s.Keys)
+	}
+}
+
+// Stats returns statistics about the shape and size of the Trie. It visits
+// every node, so it takes time proportional to the size of the Trie.
+func (tn *Alpha) Stats() Stats {
+	var s Stats
+	var depths uint64
+	s.HeapBytes = uint64(unsafe.Sizeof(*tn))
+	if h := tn.header(); h != nil {
+		s.HeapBytes += uint64(unsafe.Sizeof(*h))
+	}
+	tn.stats(&s, 0, &depths)
+	s.finish(depths)
+	return s
+}
+
+func (tn *Alpha) stats(s *Stats, depth int, depths *uint64) {
+	s.node(depth, len(tn.children))
+	s.PrefixBytes += uint64(len(tn.prefix))
+	s.HeapBytes += uint64(len(tn.prefix)) + uint64(cap(tn.children))*uint64(unsafe.Sizeof(tn))
+	for i, child := range tn.children {
+		s.HeapBytes += uint64(unsafe.Sizeof(*child))
+		if i == 0 && child.prefix == "" {
+			// The value node is found at the depth of its parent.
+			s.node(depth+1, 0)
+			s.Keys++
+			*depths += uint64(depth)
+			if fv, ok := child.value.(*foldedValue); ok {
+				s.HeapBytes += uint64(unsafe.Sizeof(*fv)) + uint64(len(fv.key))
+			}
+			continue
+		}
+		child.stats(s, depth+1, depths)
+	}
+}
+
+// Stats returns statistics about the shape and size of the DST. It visits every
+// node, so it takes time proportional to the size of the DST. Branches left
+// behind by Delete are included in its node counts, but only nodes at the
+// depth of the final bit of a key, or the keys in their bitmaps, are counted
+// as keys.
+func (dst *Bravo) Stats() Stats {
+	var s Stats
+	s.HeapBytes = uint64(unsafe.Sizeof(*dst))
+	if dst.root != nil {
+		dst.stats(dst.root, &s, initialMask)
+	}
+	s.finish(s.Keys * uint64(64-dst.leafBits))
+	return s
+}
+
+// stats records the node, and the nodes below it, where mask is the bit of the
+// key below the node.
+func (dst *Bravo) stats(n *bnode, s *Stats, mask uint64) {
+	s.HeapBytes += uint64(unsafe.Sizeof(*n))
+	if tail := dst.tail(); mask <= tail {
+		s.node(bits.LeadingZeros64(mask), 0)
+		s.Keys += uint64(bits.OnesCount64(n.leaves(tail)))
+		return
+	}
+	var children int
+	if n.left != nil {
+		children++
+		dst.stats(n.left, s, mask>>1)
+	}
+	if n.right != nil {
+		children++
+		dst.stats(n.right, s, mask>>1)
+	}
+	s.node(bits.LeadingZeros64(mask), children)
+}
+
+// Stats returns statistics about the shape and size of the tree. It visits
+// every node, so it takes time proportional to the size of the tree. Branches
+// left behind by Delete are included in its node counts, but only nodes at the
+// depth of the final digit of a key, or the keys in their bitmaps, are counted
+// as keys.
+func (tree *Charlie) Stats() Stats {
+	var s Stats
+	end := tree.endLevel()
+	s.HeapBytes = uint64(unsafe.Sizeof(*tree))
+	if tree.head != nil {
+		tree.stats(tree.head, &s, 0)
+	}
+	s.finish(s.Keys * uint64(end))
+	return s
+}
+
+func (tree *Charlie) stats(n *cnode, s *Stats, depth int) {
+	var children int
+	for _, child := range n.children {
+		if child != nil {
+			children++
+			tree.stats(child, s, depth+1)
+		}
+	}
+	s.node(depth, children)
+	if depth == tree.endLevel() {
+		s.Keys += tree.leafCount(n)
+	}
+	var f *cnode
+	s.HeapBytes += uint64(unsafe.Sizeof(*n)) + uint64(cap(n.children))*uint64(unsafe.Sizeof(f)) + uint64(cap(n.digits))*8
+}
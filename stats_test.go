@@ -0,0 +1,101 @@
+package goradix
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+func TestAlphaStats(t *testing.T) {
+	trie := new(Alpha)
+	trie.Store("sam", 1)
+	trie.Store("samuel", 2)
+	trie.Store("sally", 3)
+
+	// root -> "sa" -> "m" -> value
+	//              |      -> "uel" -> value
+	//              -> "lly" -> value
+	s := trie.Stats()
+
+	if got, want := s.Nodes, uint64(8); got != want {
+		t.Errorf("Nodes: GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := s.Keys, uint64(3); got != want {
+		t.Errorf("Keys: GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := s.MaxDepth, 4; got != want {
+		t.Errorf("MaxDepth: GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := s.AverageDepth, 7.0/3; got != want {
+		t.Errorf("AverageDepth: GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := s.Fanout, []uint64{3, 3, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fanout: GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := s.PrefixBytes, uint64(9); got != want {
+		t.Errorf("PrefixBytes: GOT: %v; WANT: %v", got, want)
+	}
+	if s.HeapBytes == 0 {
+		t.Errorf("HeapBytes: GOT: %v; WANT: > 0", s.HeapBytes)
+	}
+}
+
+func TestAlphaStatsEmpty(t *testing.T) {
+	s := new(Alpha).Stats()
+	want := Stats{Nodes: 1, Fanout: []uint64{1}, HeapBytes: s.HeapBytes}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("GOT: %+v; WANT: %+v", s, want)
+	}
+}
+
+func TestBravoStats(t *testing.T) {
+	dst := NewBravo()
+	dst.Store(0)
+	dst.Store(1)
+	dst.Store(2)
+	dst.Delete(2)
+
+	s := dst.Stats()
+
+	if got, want := s.Keys, uint64(2); got != want {
+		t.Errorf("Keys: GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := s.Nodes, uint64(dst.Count+1); got != want {
+		t.Errorf("Nodes: GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := s.MaxDepth, 64; got != want {
+		t.Errorf("MaxDepth: GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := s.AverageDepth, 64.0; got != want {
+		t.Errorf("AverageDepth: GOT: %v; WANT: %v", got, want)
+	}
+	// Two leaves, the dead branch left behind by Delete, and two forks.
+	if got, want := s.Fanout, []uint64{3, s.Nodes - 5, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fanout: GOT: %v; WANT: %v", got, want)
+	}
+}
+
+func TestCharlieStats(t *testing.T) {
+	tree := NewCharlie(4)
+	tree.Store(0)
+	tree.Store(0xF)
+
+	s := tree.Stats()
+
+	if got, want := s.Keys, uint64(2); got != want {
+		t.Errorf("Keys: GOT: %v; WANT: %v", got, want)
+	}
+	// Both keys share the root node and the 15 nodes below it.
+	if got, want := s.Nodes, uint64(18); got != want {
+		t.Errorf("Nodes: GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := s.MaxDepth, 16; got != want {
+		t.Errorf("MaxDepth: GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := s.Fanout, []uint64{2, 15, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fanout: GOT: %v; WANT: %v", got, want)
+	}
+	if got, want := s.HeapBytes, uint64(unsafe.Sizeof(*tree))+18*uint64(unsafe.Sizeof(cnode{})+16*unsafe.Sizeof(tree.head)); got != want {
+		t.Errorf("HeapBytes: GOT: %v; WANT: %v", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+package goradix
+
+import "log"
+
+// TraceKind describes what happened at a node of a Trie.
+type TraceKind int
+
+const (
+	// TraceDescend means the operation moved from a node to one of its
+	// children.
+	TraceDescend TraceKind = iota
+
+	// TraceFound means the operation found the node it was looking for.
+	TraceFound
+
+	// TraceMiss means the operation found that the key is not in the Trie.
+	TraceMiss
+
+	// TraceInsert means a new node was inserted below a node.
+	TraceInsert
+
+	// TraceUpdate means the value of a key already in the Trie was replaced.
+	TraceUpdate
+
+	// TraceSplit means a node was split in two, so that a key that shares only
+	// part of its prefix could be inserted.
+	TraceSplit
+
+	// TraceRemove means a node was removed from the Trie.
+	TraceRemove
+
+	// TraceMerge means a node was merged with its only child.
+	TraceMerge
+)
+
+func (k TraceKind) String() string {
+	switch k {
+	case TraceDescend:
+		return "descend"
+	case TraceFound:
+		return "found"
+	case TraceMiss:
+		return "miss"
+	case TraceInsert:
+		return "insert"
+	case TraceUpdate:
+		return "update"
+	case TraceSplit:
+		return "split"
+	case TraceRemove:
+		return "remove"
+	case TraceMerge:
+		return "merge"
+	default:
+		return "unknown"
+	}
+}
+
+// TraceEvent describes one step of an operation on a Trie.
+type TraceEvent struct {
+	// Op is the name of the method that is running, e.g., "Store".
+	Op string
+
+	// Kind describes what happened.
+	Kind TraceKind
+
+	// Key is the part of the key, or for Keys, of the prefix, that remains
+	// to be matched below the node.
+	Key string
+
+	// Prefix is the prefix of the node where the event happened, which for
+	// TraceDescend and TraceInsert is the child node, and for TraceSplit is
+	// the part of the prefix shared by both keys.
+	Prefix string
+}
+
+// Tracer receives the steps of the operations on a Trie, for debugging. The
+// strings of an event may share storage with the keys given to the Trie, and
+// must be copied to be retained after Trace returns.
+type Tracer interface {
+	Trace(event TraceEvent)
+}
+
+// LogTracer is a Tracer that writes each event to a log.Logger.
+type LogTracer struct {
+	Logger *log.Logger // when nil, the standard logger is used
+}
+
+// Trace writes the event to the Logger.
+func (lt LogTracer) Trace(event TraceEvent) {
+	const format = "%s %s: key: %q; prefix: %q"
+	if lt.Logger == nil {
+		log.Printf(format, event.Op, event.Kind, event.Key, event.Prefix)
+		return
+	}
+	lt.Logger.Printf(format, event.Op, event.Kind, event.Key, event.Prefix)
+}
+
+// SetTracer arranges for the tracer to receive the steps of each Store, Load,
+// Delete, and Keys operation on the Trie. When tracer is nil, tracing is
+// turned off, and costs nothing beyond checking whether it is on.
+func (tn *Alpha) SetTracer(tracer Tracer) {
+	tn.ensureHeader().tracer = tracer
+}
+
+// tracer returns the Tracer of the Trie, or nil when it has none.
+func (tn *Alpha) tracer() Tracer {
+	if h := tn.header(); h != nil {
+		return h.tracer
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package goradix
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"testing"
+)
+
+// traceRecorder is a Tracer that records the kind and prefix of each event.
+type traceRecorder []string
+
+func (tr *traceRecorder) Trace(event TraceEvent) {
+	*tr = append(*tr, event.Op+" "+event.Kind.String()+" "+event.Prefix)
+}
+
+func TestAlphaTracer(t *testing.T) {
+	trie := new(Alpha)
+	trie.Store("sally", 1)
+	trie.Store("samuel", 2)
+
+	var got traceRecorder
+	trie.SetTracer(&got)
+
+	trie.Store("sam", 3)
+	trie.Load("sal")
+	trie.Delete("samuel")
+	trie.Keys("sa", 0)
+
+	want := traceRecorder{
+		"Store descend sa",
+		"Store descend muel",
+		"Store split m",
+		"Load descend sa",
+		"Load descend lly",
+		"Load miss lly",
+		"Delete descend sa",
+		"Delete descend m",
+		"Delete descend uel",
+		"Delete remove uel",
+		"Delete remove uel",
+		"Keys descend sa",
+		"Keys found sa",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("\nGOT:  %q\nWANT: %q", got, want)
+	}
+}
+
+func TestAlphaTracerMerge(t *testing.T) {
+	trie := new(Alpha)
+	trie.Store("roberta", 1)
+	trie.Store("roberto", 2)
+
+	var got traceRecorder
+	trie.SetTracer(&got)
+	trie.Delete("roberta")
+
+	if want := "Delete merge robert"; got[len(got)-1] != want {
+		t.Errorf("GOT: %q; WANT: %q", got[len(got)-1], want)
+	}
+
+	// Once turned off, no more events are sent.
+	trie.SetTracer(nil)
+	trie.Store("robert", 3)
+	if want := "Delete merge robert"; got[len(got)-1] != want {
+		t.Errorf("GOT: %q; WANT: %q", got[len(got)-1], want)
+	}
+}
+
+func TestLogTracer(t *testing.T) {
+	var bb bytes.Buffer
+	trie := new(Alpha)
+	trie.SetTracer(LogTracer{Logger: log.New(&bb, "", 0)})
+	trie.Store("a", 1)
+
+	if got, want := bb.String(), "Store insert: key: \"\"; prefix: \"a\"\n"; got != want {
+		t.Errorf("GOT: %q; WANT: %q", got, want)
+	}
+}
+
+func TestAlphaTracerOffDoesNotAllocate(t *testing.T) {
+	trie := new(Alpha)
+	trie.Store("sally", 1)
+	trie.Store("samuel", 2)
+	trie.SetTracer(nil)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		trie.Load("sam")
+		trie.Load("samuel")
+		trie.Store("samuel", 2)
+	})
+	if allocs != 0 {
+		t.Errorf("GOT: %v; WANT: %v", allocs, 0)
+	}
+}